@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/casbin/casbin/v3/model"
 	"github.com/casbin/casbin/v3/persist"
@@ -37,25 +38,21 @@ var (
 	_ persist.ContextUpdatableAdapter = new(Adapter)
 )
 
-// the supported driver names.
-var supportedDriverNames = map[adapterDriverNameIndex][]string{
-	_SQLite:     {"sqlite", "sqlite3", "nrsqlite3"},
-	_MySQL:      {"mysql", "nrmysql"},
-	_PostgreSQL: {"postgres", "pgx", "pq-timeouts", "cloudsql-postgres", "ql", "nrpostgres", "cockroach"},
-	_SQLServer:  {"sqlserver", "azuresql"},
-}
-
 // NewAdapter  the constructor for Adapter.
 // db should connected to database and controlled by user.
 // If tableName == "", the Adapter will automatically create a table named "casbin_rule".
-func NewAdapter(db *sql.DB, driverName, tableName string) (*Adapter, error) {
-	return NewAdapterWithContext(context.Background(), db, driverName, tableName)
+// tableName can be qualified by the schema, e.g. "analytics.casbin_rule", see WithSchema.
+// opts are optional, e.g. WithColumnCount.
+func NewAdapter(db *sql.DB, driverName, tableName string, opts ...Option) (*Adapter, error) {
+	return NewAdapterWithContext(context.Background(), db, driverName, tableName, opts...)
 }
 
 // NewAdapterWithContext  the constructor for Adapter.
 // db should connected to database and controlled by user.
 // If tableName == "", the Adapter will automatically create a table named "casbin_rule".
-func NewAdapterWithContext(ctx context.Context, db *sql.DB, driverName, tableName string) (*Adapter, error) {
+// tableName can be qualified by the schema, e.g. "analytics.casbin_rule", see WithSchema.
+// opts are optional, e.g. WithColumnCount.
+func NewAdapterWithContext(ctx context.Context, db *sql.DB, driverName, tableName string, opts ...Option) (*Adapter, error) {
 	// check parameters first
 	if ctx == nil {
 		return nil, errors.New("ctx is nil")
@@ -65,16 +62,45 @@ func NewAdapterWithContext(ctx context.Context, db *sql.DB, driverName, tableNam
 		return nil, errors.New("db is nil")
 	}
 
-	driverNameIndex, err := getAdapterDriverNameIndex(driverName)
+	dialect, err := LookupDialect(driverName)
 	if err != nil {
 		return nil, err
 	}
 
-	if tableName == "" {
-		tableName = defaultTableName
+	return newAdapter(ctx, db, dialect, tableName, opts)
+}
+
+// NewAdapterFromDB  the constructor for Adapter, the dialect is detected from db, see DetectDialect.
+// db should connected to database and controlled by user.
+// If tableName == "", the Adapter will automatically create a table named "casbin_rule".
+// opts are optional, e.g. WithColumnCount.
+func NewAdapterFromDB(db *sql.DB, tableName string, opts ...Option) (*Adapter, error) {
+	return NewAdapterFromDBWithContext(context.Background(), db, tableName, opts...)
+}
+
+// NewAdapterFromDBWithContext  the constructor for Adapter, the dialect is detected from db, see DetectDialect.
+// db should connected to database and controlled by user.
+// If tableName == "", the Adapter will automatically create a table named "casbin_rule".
+// opts are optional, e.g. WithColumnCount.
+func NewAdapterFromDBWithContext(ctx context.Context, db *sql.DB, tableName string, opts ...Option) (*Adapter, error) {
+	dialect, err := DetectDialect(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAdapter(ctx, db, dialect, tableName, opts)
+}
+
+// newAdapter create the Adapter, and provision or migrate the table.
+func newAdapter(ctx context.Context, db *sql.DB, dialect Dialect, tableName string, opts []Option) (*Adapter, error) {
+	dao, options, err := prepareDao(db, dialect, tableName, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	dao := newDao(db, driverNameIndex, tableName)
+	if options.copyFrom && !options.ignoreDuplicates && dao.sqlCopyFrom != "" {
+		dao.copyFrom = copyFromOf(db.Driver())
+	}
 
 	// check db connection
 	err = db.PingContext(ctx)
@@ -83,34 +109,157 @@ func NewAdapterWithContext(ctx context.Context, db *sql.DB, driverName, tableNam
 	}
 
 	// check adapter table
-	if !dao.IsTableExist(ctx) {
-		if err = dao.CreateTable(ctx); err != nil {
+	dao, exist, err := dao.existingDao(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	adapter := &Adapter{ctx: ctx, dao: dao}
+
+	switch {
+	case exist:
+		if options.autoMigrate {
+			err = adapter.Migrate(ctx)
+		} else {
+			err = adapter.checkMigrations(ctx)
+		}
+		if err != nil {
+			return nil, err
+		}
+	case options.withoutDDL:
+		return nil, fmt.Errorf("%w: %s", ErrTableNotExist, dao.table)
+	default:
+		if err = dao.Provision(ctx); err != nil {
 			return nil, err
 		}
 	}
 
-	return &Adapter{ctx: ctx, dao: dao}, nil
+	// the statements are cached after the table is ready.
+	adapter.dao.stmts = newStmtCache(db, options.stmtCacheSize)
+
+	return adapter, nil
 }
 
-func getAdapterDriverNameIndex(driverName string) (adapterDriverNameIndex, error) {
-	for driverNameIndex, drivers := range supportedDriverNames {
-		for _, supportedDriver := range drivers {
-			if driverName == supportedDriver {
-				return driverNameIndex, nil
-			}
-		}
+// CreateTable  create the table with the same schema as NewAdapter, if it does not exist.
+// It is used to provision the table by a database user with DDL privileges,
+// when the Adapter is created WithoutDDL.
+func CreateTable(ctx context.Context, db *sql.DB, driverName, tableName string, opts ...Option) error {
+	dao, exist, err := lookupDao(ctx, db, driverName, tableName, opts)
+	if err != nil || exist {
+		return err
 	}
 
-	switch driverName {
-	case "mssql":
-		return 0, errors.New("driver name mssql not support, please use sqlserver")
-	case "oci8", "ora", "goracle":
-		return 0, errors.New("sqladapter: please checkout 'oracle' branch")
-	default:
-		return 0, fmt.Errorf("unsupported driver name: %s", driverName)
+	return dao.Provision(ctx)
+}
+
+// MigrateTable  apply the pending schema migrations of the options to the table, see Adapter.Migrate.
+// The table is created if it does not exist.
+// It is used to upgrade the table by a database user with DDL privileges,
+// when the Adapter is created without WithAutoMigrate.
+func MigrateTable(ctx context.Context, db *sql.DB, driverName, tableName string, opts ...Option) error {
+	dao, exist, err := lookupDao(ctx, db, driverName, tableName, opts)
+	if err != nil {
+		return err
+	}
+
+	if !exist {
+		return dao.Provision(ctx)
+	}
+
+	return Adapter{dao: dao}.Migrate(ctx)
+}
+
+// PendingMigrations  returns the schema migrations of the options which are not applied to the table yet,
+// they are applied by MigrateTable or WithAutoMigrate. It only reads the database.
+// All the migrations of the options are pending if the table does not exist.
+func PendingMigrations(ctx context.Context, db *sql.DB, driverName, tableName string, opts ...Option) ([]Migration, error) {
+	dao, exist, err := lookupDao(ctx, db, driverName, tableName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return publicMigrations(dao.enabledMigrations()), nil
+	}
+
+	return dao.PendingMigrations(ctx)
+}
+
+// lookupDao  returns the dao of the table, and whether the table exists.
+func lookupDao(ctx context.Context, db *sql.DB, driverName, tableName string, opts []Option) (dao, bool, error) {
+	if ctx == nil {
+		return dao{}, false, errors.New("ctx is nil")
+	}
+
+	if db == nil {
+		return dao{}, false, errors.New("db is nil")
+	}
+
+	dialect, err := LookupDialect(driverName)
+	if err != nil {
+		return dao{}, false, err
+	}
+
+	d, options, err := prepareDao(db, dialect, tableName, opts)
+	if err != nil {
+		return dao{}, false, err
+	}
+
+	return d.existingDao(ctx, options)
+}
+
+// CreateTableSQL  returns the DDL statements executed by CreateTable, for reviewing or running them manually.
+func CreateTableSQL(driverName, tableName string, opts ...Option) (string, error) {
+	dialect, err := LookupDialect(driverName)
+	if err != nil {
+		return "", err
+	}
+
+	dao, _, err := prepareDao(nil, dialect, tableName, opts)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(dao.ProvisionSQL(), "\n"), nil
+}
+
+// prepareDao check the parameters and create the dao.
+func prepareDao(db *sql.DB, dialect Dialect, tableName string, opts []Option) (dao, options, error) {
+	if tableName == "" {
+		tableName = defaultTableName
+	}
+
+	o, err := newOptions(opts)
+	if err != nil {
+		return dao{}, o, err
+	}
+
+	o.schema, tableName = splitTableName(o.schema, tableName)
+	if err = validateTableName(o.schema, tableName); err != nil {
+		return dao{}, o, err
+	}
+
+	if err = dialect.Templates().validate(o); err != nil {
+		return dao{}, o, fmt.Errorf("sqladapter: the dialect %s does not support the options: %w", dialect.Name(), err)
 	}
+
+	return newDao(db, dialect, tableName, o), o, nil
+}
+
+// DBTX  the executor of the SQL, it is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
 }
 
+var (
+	_ DBTX = new(sql.DB)
+	_ DBTX = new(sql.Tx)
+	_ DBTX = new(sql.Conn)
+)
+
 // Adapter  defines the database adapter for Casbin.
 // It can load policy lines from connected database or save policy lines.
 type Adapter struct {
@@ -119,7 +268,8 @@ type Adapter struct {
 	// Could not be removed until Casbin adapter interface support context as the first parameter.
 	ctx context.Context
 
-	filtered interface{}
+	// filtered  the filters of the loaded policy, SavePolicy only replaces the rules matched by them.
+	filtered [][]Condition
 }
 
 // loadPolicyLine  load a policy line to model.
@@ -130,21 +280,31 @@ func (Adapter) loadPolicyLine(line rule, model model.Model) error {
 }
 
 // genArgs generate args from ptype and rule.
-// expects rule to have at most maxParameterCount-1 elements.
-// It fills missing fields with empty strings, and will ignore extra fields.
-func (Adapter) genArgs(ptype string, rule []string) []interface{} {
-	args := make([]interface{}, 0, maxParameterCount)
+// It fills missing fields with empty strings,
+// and returns an error if rule has more fields than the value columns.
+func (adapter Adapter) genArgs(ptype string, rule []string) ([]interface{}, error) {
+	columnCount := len(adapter.dao.columns)
+	if len(rule) >= columnCount {
+		return nil, errRuleTooLong(len(rule), columnCount-1)
+	}
+
+	args := make([]interface{}, 0, columnCount)
 	args = append(args, ptype)
 
 	for idx := range rule {
 		args = append(args, strings.TrimSpace(rule[idx]))
 	}
 
-	for idx := len(rule) + 1; idx < maxParameterCount; idx++ {
+	for idx := len(rule) + 1; idx < columnCount; idx++ {
 		args = append(args, "")
 	}
 
-	return args
+	return args, nil
+}
+
+// errRuleTooLong .
+func errRuleTooLong(ruleLength, columnCount int) error {
+	return fmt.Errorf("policy rule has %d fields, but the table only has %d value columns, see WithColumnCount", ruleLength, columnCount)
 }
 
 // LoadPolicy  load all policy rules from the storage.
@@ -153,21 +313,13 @@ func (adapter *Adapter) LoadPolicy(model model.Model) error {
 }
 
 // LoadPolicyCtx loads all policy rules from the storage with context.
+// The rules are loaded to model while they are read, they are not collected in memory.
 func (adapter *Adapter) LoadPolicyCtx(ctx context.Context, model model.Model) error {
-	lines, err := adapter.dao.SelectAll(ctx)
-	if err != nil {
-		return err
-	}
-
 	adapter.filtered = nil
 
-	for _, line := range lines {
-		if err = adapter.loadPolicyLine(line, model); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return adapter.dao.SelectByFilter(ctx, nil, func(line rule) error {
+		return adapter.loadPolicyLine(line, model)
+	})
 }
 
 // SavePolicy  save policy rules to the storage.
@@ -176,28 +328,64 @@ func (adapter Adapter) SavePolicy(model model.Model) error {
 }
 
 // SavePolicyCtx saves all policy rules to the storage with context.
+// If the policy is loaded by LoadFilteredPolicy, only the rules matched by the filter are replaced in one transaction,
+// the other rules, e.g. the rules of the other tenants, are kept.
+// It returns ErrRuleOutOfFilter if a rule of model is not matched by the filter in the database, nothing is saved then.
 func (adapter Adapter) SavePolicyCtx(ctx context.Context, model model.Model) error {
+	if adapter.dao.diffSave {
+		_, err := adapter.SavePolicyDiff(ctx, model)
+
+		return err
+	}
+
+	args, err := adapter.modelArgs(model)
+	if err != nil {
+		return err
+	}
+
 	if adapter.filtered != nil {
-		return errors.New("could not save filtered policies")
+		return adapter.dao.ReplaceFilteredRows(ctx, adapter.filtered, args)
 	}
 
-	args := make([][]interface{}, 0, 128)
+	return adapter.dao.DeleteAllAndInsertRows(ctx, args)
+}
 
-	for ptype, ast := range model["p"] {
-		for _, rule := range ast.Policy {
-			arg := adapter.genArgs(ptype, rule)
-			args = append(args, arg)
-		}
+// SavePolicyDiff  save policy rules to the storage by the difference with the stored rules,
+// it reads the stored rules, inserts the new rules and deletes the removed rules in one transaction.
+// The unchanged rules keep their ids and timestamps.
+// If the policy is loaded by LoadFilteredPolicy, only the stored rules matched by the filter are compared.
+func (adapter Adapter) SavePolicyDiff(ctx context.Context, model model.Model) (SaveResult, error) {
+	args, err := adapter.modelArgs(model)
+	if err != nil {
+		return SaveResult{}, err
+	}
+
+	added, removed, err := adapter.dao.DiffRows(ctx, adapter.filtered, args)
+	if err != nil {
+		return SaveResult{}, err
 	}
 
-	for ptype, ast := range model["g"] {
-		for _, rule := range ast.Policy {
-			arg := adapter.genArgs(ptype, rule)
-			args = append(args, arg)
+	return SaveResult{Added: added, Removed: removed}, nil
+}
+
+// modelArgs generate the args of the rules in the p and g sections of model.
+func (adapter Adapter) modelArgs(model model.Model) ([][]interface{}, error) {
+	args := make([][]interface{}, 0, 128)
+
+	for _, sec := range []string{"p", "g"} {
+		for ptype, ast := range model[sec] {
+			for _, rule := range ast.Policy {
+				arg, err := adapter.genArgs(ptype, rule)
+				if err != nil {
+					return nil, err
+				}
+
+				args = append(args, arg)
+			}
 		}
 	}
 
-	return adapter.dao.DeleteAllAndInsertRows(ctx, args)
+	return args, nil
 }
 
 // AddPolicy  add one policy rule to the storage.
@@ -208,7 +396,10 @@ func (adapter Adapter) AddPolicy(sec string, ptype string, rule []string) error
 // AddPolicyCtx adds a policy rule to the storage with context.
 // This is part of the Auto-Save feature.
 func (adapter Adapter) AddPolicyCtx(ctx context.Context, sec string, ptype string, rule []string) error {
-	args := adapter.genArgs(ptype, rule)
+	args, err := adapter.genArgs(ptype, rule)
+	if err != nil {
+		return err
+	}
 
 	return adapter.dao.InsertRow(ctx, args...)
 }
@@ -224,7 +415,11 @@ func (adapter Adapter) AddPoliciesCtx(ctx context.Context, sec string, ptype str
 	args := make([][]interface{}, 0, len(rules))
 
 	for _, rule := range rules {
-		arg := adapter.genArgs(ptype, rule)
+		arg, err := adapter.genArgs(ptype, rule)
+		if err != nil {
+			return err
+		}
+
 		args = append(args, arg)
 	}
 
@@ -267,7 +462,11 @@ func (adapter Adapter) RemovePoliciesCtx(ctx context.Context, sec string, ptype
 	args := make([][]interface{}, len(rules))
 
 	for idx, rule := range rules {
-		arg := adapter.genArgs(ptype, rule)
+		arg, err := adapter.genArgs(ptype, rule)
+		if err != nil {
+			return err
+		}
+
 		args[idx] = arg
 	}
 
@@ -275,7 +474,9 @@ func (adapter Adapter) RemovePoliciesCtx(ctx context.Context, sec string, ptype
 }
 
 // LoadFilteredPolicy  load policy rules that match the Filter.
-// filterPtr must be a pointer.
+// filterPtr can be a Filter or FilterEx, the pointer of them, or a slice of them to load the rules matched by any of them.
+// It can also be the text filter, e.g. "p, , data1", which has a filter per line, the empty values match all the values.
+// Otherwise, it returns an *InvalidFilterError.
 func (adapter *Adapter) LoadFilteredPolicy(model model.Model, filterPtr interface{}) error {
 	return adapter.LoadFilteredPolicyCtx(adapter.ctx, model, filterPtr)
 }
@@ -283,28 +484,96 @@ func (adapter *Adapter) LoadFilteredPolicy(model model.Model, filterPtr interfac
 // LoadFilteredPolicyCtx loads only policy rules that match the filter.
 func (adapter *Adapter) LoadFilteredPolicyCtx(ctx context.Context, model model.Model, filterPtr interface{}) error {
 	if filterPtr == nil {
-		return adapter.LoadPolicy(model)
+		return adapter.LoadPolicyCtx(ctx, model)
 	}
 
-	filter, ok := filterPtr.(*Filter)
-	if !ok {
-		return errors.New("invalid filter type")
+	filters, err := genFilters(filterPtr)
+	if err != nil {
+		return err
 	}
 
-	lines, err := adapter.dao.SelectByFilter(ctx, filter.genData())
+	err = adapter.dao.SelectByFilter(ctx, filters, func(line rule) error {
+		return adapter.loadPolicyLine(line, model)
+	})
 	if err != nil {
 		return err
 	}
 
-	for _, line := range lines {
-		if err = adapter.loadPolicyLine(line, model); err != nil {
-			return err
+	adapter.filtered = filters
+
+	return nil
+}
+
+// ForEachRule  call fn with each policy rule matched by filterPtr, the rules are read one by one without loading a model.
+// filterPtr accepts the same filters as LoadFilteredPolicy, if it is nil, all the rules are read.
+// It stops and returns the error of fn.
+// The connection is held until the last rule, so fn should not wait for another connection of the same pool.
+func (adapter Adapter) ForEachRule(ctx context.Context, filterPtr interface{}, fn func(ptype string, rule []string) error) error {
+	if fn == nil {
+		return errors.New("fn is nil")
+	}
+
+	filters, err := genOptionalFilters(filterPtr)
+	if err != nil {
+		return err
+	}
+
+	return adapter.dao.SelectByFilter(ctx, filters, func(line rule) error {
+		data := line.Data()
+		if len(data) == 0 {
+			return nil
 		}
+
+		return fn(data[0], data[1:])
+	})
+}
+
+// LoadAuditedRules  load the policy rules matched by filterPtr with the audit timestamps, see WithTimestamps.
+// filterPtr accepts the same filters as LoadFilteredPolicy, if it is nil, all the rules are loaded.
+func (adapter Adapter) LoadAuditedRules(ctx context.Context, filterPtr interface{}) ([]AuditedRule, error) {
+	if !adapter.dao.timestamps {
+		return nil, ErrTimestampsNotEnabled
 	}
 
-	adapter.filtered = struct{}{}
+	filters, err := genOptionalFilters(filterPtr)
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	return adapter.dao.SelectAudited(ctx, filters)
+}
+
+// PurgeDeleted  delete the soft deleted rules physically, if they are deleted earlier than age ago by the database clock.
+// It returns the number of the purged rules, see WithSoftDelete.
+func (adapter Adapter) PurgeDeleted(ctx context.Context, age time.Duration) (int64, error) {
+	if !adapter.dao.softDelete {
+		return 0, ErrSoftDeleteNotEnabled
+	}
+
+	if age < 0 {
+		return 0, fmt.Errorf("invalid age: %s, it must not be negative", age)
+	}
+
+	return adapter.dao.PurgeDeleted(ctx, int64(age/time.Second))
+}
+
+// WithTx  returns a copy of the Adapter which executes the SQL by tx, e.g. a *sql.Tx or *sql.Conn owned by the caller.
+// The policy loads also use tx instead of the db of WithReadDB.
+// If tx is a *sql.Tx, the policy writes are in the transaction, they are committed or rolled back by the caller,
+// and the caller should roll back the transaction if a write fails.
+func (adapter *Adapter) WithTx(tx DBTX) *Adapter {
+	a := *adapter
+	a.dao.db = tx
+	a.dao.readDB = tx
+
+	return &a
+}
+
+// Close  close the cached prepared statements, see WithStmtCacheSize.
+// The Adapter still works after Close, but the statements are not cached, the db is not closed.
+// The Adapters of WithTx share the cache with adapter.
+func (adapter *Adapter) Close() error {
+	return adapter.dao.stmts.close()
 }
 
 // IsFiltered  returns true if the loaded policy rules has been filtered.
@@ -326,8 +595,15 @@ func (adapter Adapter) UpdatePolicy(sec, ptype string, oldRule, newRule []string
 // UpdatePolicyCtx updates a policy rule from storage.
 // This is part of the Auto-Save feature.
 func (adapter Adapter) UpdatePolicyCtx(ctx context.Context, sec string, ptype string, oldRule, newRule []string) error {
-	oldArgs := adapter.genArgs(ptype, oldRule)
-	newArgs := adapter.genArgs(ptype, newRule)
+	oldArgs, err := adapter.genArgs(ptype, oldRule)
+	if err != nil {
+		return err
+	}
+
+	newArgs, err := adapter.genArgs(ptype, newRule)
+	if err != nil {
+		return err
+	}
 
 	return adapter.dao.UpdateRow(ctx, append(newArgs, oldArgs...)...)
 }
@@ -346,8 +622,16 @@ func (adapter Adapter) UpdatePoliciesCtx(ctx context.Context, sec string, ptype
 	args := make([][]interface{}, 0, len(oldRules)+len(newRules))
 
 	for idx := range oldRules {
-		oldArgs := adapter.genArgs(ptype, oldRules[idx])
-		newArgs := adapter.genArgs(ptype, newRules[idx])
+		oldArgs, err := adapter.genArgs(ptype, oldRules[idx])
+		if err != nil {
+			return err
+		}
+
+		newArgs, err := adapter.genArgs(ptype, newRules[idx])
+		if err != nil {
+			return err
+		}
+
 		args = append(args, append(newArgs, oldArgs...))
 	}
 
@@ -363,18 +647,22 @@ func (adapter Adapter) UpdateFilteredPolicies(sec, ptype string, newRules [][]st
 func (adapter Adapter) UpdateFilteredPoliciesCtx(ctx context.Context, sec string, ptype string, newRules [][]string, fieldIndex int, fieldValues ...string) (oldPolicies [][]string, err error) {
 	whereCondition, whereArgs := adapter.dao.GenFilteredCondition(ptype, fieldIndex, fieldValues...)
 
+	args := make([][]interface{}, 0, len(newRules))
+	for _, policy := range newRules {
+		var arg []interface{}
+		if arg, err = adapter.genArgs(ptype, policy); err != nil {
+			return
+		}
+
+		args = append(args, arg)
+	}
+
 	var oldRules []rule
 	oldRules, err = adapter.dao.SelectByCondition(ctx, whereCondition, whereArgs...)
 	if err != nil {
 		return
 	}
 
-	args := make([][]interface{}, 0, len(newRules))
-	for _, policy := range newRules {
-		arg := adapter.genArgs(ptype, policy)
-		args = append(args, arg)
-	}
-
 	if err = adapter.dao.UpdateFilteredRows(ctx, whereCondition, whereArgs, args); err != nil {
 		return
 	}
@@ -17,6 +17,7 @@ package sqladapter
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"testing"
 )
 
@@ -27,6 +28,7 @@ func TestNewAdapterWithContext(t *testing.T) {
 		db         *sql.DB
 		driverName string
 		tableName  string
+		opts       []Option
 	}
 
 	tests := []struct {
@@ -61,7 +63,7 @@ func TestNewAdapterWithContext(t *testing.T) {
 			name: "04 unsupported driver",
 			params: params{
 				ctx:        context.TODO(),
-				driverName: "oci8",
+				driverName: "db2",
 				db:         &sql.DB{},
 			},
 			wantErr: true,
@@ -93,11 +95,91 @@ func TestNewAdapterWithContext(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "08 invalid column count",
+			params: params{
+				ctx:        context.TODO(),
+				driverName: "sqlite",
+				db:         &sql.DB{},
+				opts:       []Option{WithColumnCount(0)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "09 invalid column count",
+			params: params{
+				ctx:        context.TODO(),
+				driverName: "sqlite",
+				db:         &sql.DB{},
+				opts:       []Option{WithColumnCount(maxColumnCount + 1)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "10 invalid column mapping",
+			params: params{
+				ctx:        context.TODO(),
+				driverName: "sqlite",
+				db:         &sql.DB{},
+				opts:       []Option{WithColumnCount(2), WithColumnMapping(map[string]string{"v2": "obj"})},
+			},
+			wantErr: true,
+		},
+		{
+			name: "11 duplicate column mapping",
+			params: params{
+				ctx:        context.TODO(),
+				driverName: "sqlite",
+				db:         &sql.DB{},
+				opts:       []Option{WithColumnMapping(map[string]string{"v0": "v1"})},
+			},
+			wantErr: true,
+		},
+		{
+			name: "12 invalid table name",
+			params: params{
+				ctx:        context.TODO(),
+				driverName: "sqlite",
+				db:         &sql.DB{},
+				tableName:  "casbin_rule; DROP TABLE users",
+			},
+			wantErr: true,
+		},
+		{
+			name: "13 invalid schema name",
+			params: params{
+				ctx:        context.TODO(),
+				driverName: "postgres",
+				db:         &sql.DB{},
+				tableName:  `"analytics".casbin_rule`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "14 invalid column name",
+			params: params{
+				ctx:        context.TODO(),
+				driverName: "mysql",
+				db:         &sql.DB{},
+				opts:       []Option{WithColumnMapping(map[string]string{"p_type": "p`type"})},
+			},
+			wantErr: true,
+		},
+		{
+			name: "15 conflict options",
+			params: params{
+				ctx:        context.TODO(),
+				driverName: "mysql",
+				db:         &sql.DB{},
+				opts:       []Option{WithoutDDL(), WithAutoMigrate()},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a, err := NewAdapterWithContext(tt.params.ctx, tt.params.db, tt.params.driverName, tt.params.tableName)
+			a, err := NewAdapterWithContext(tt.params.ctx, tt.params.db, tt.params.driverName, tt.params.tableName, tt.params.opts...)
 			if tt.wantErr {
 				if a != nil || err == nil {
 					t.Errorf("test case[%s] failed", tt.name)
@@ -110,3 +192,26 @@ func TestNewAdapterWithContext(t *testing.T) {
 		})
 	}
 }
+
+// nolint: paralleltest
+func TestCreateTableSQL(t *testing.T) {
+	query, err := CreateTableSQL("postgres", "analytics.casbin_rule", WithColumnCount(2), WithUniqueIndex())
+	if err != nil {
+		t.Fatalf("CreateTableSQL failed, err: %v", err)
+	}
+
+	for _, want := range []string{
+		`CREATE TABLE IF NOT EXISTS "analytics"."casbin_rule"(`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS "uk_casbin_rule" ON "analytics"."casbin_rule" ("p_type","v0","v1");`,
+		`CREATE TABLE IF NOT EXISTS "analytics"."casbin_rule_migrations"(`,
+		`INSERT INTO "analytics"."casbin_rule_migrations" (version,description) VALUES (2,`,
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("CreateTableSQL failed, want: %s, got: %s", want, query)
+		}
+	}
+
+	if _, err = CreateTableSQL("mssql", ""); err == nil {
+		t.Error("CreateTableSQL failed, want an error for the unsupported driver")
+	}
+}
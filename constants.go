@@ -18,88 +18,165 @@ const (
 	// defaultTableName  if tableName == "", the Adapter will use this default table name.
 	defaultTableName = "casbin_rule"
 
-	// maxParameterCount .
-	maxParameterCount = 7
+	// defaultColumnCount  the default number of policy value columns, v0 to v5.
+	defaultColumnCount = 6
+
+	// maxColumnCount  the maximum number of policy value columns.
+	maxColumnCount = 32
+
+	// defaultBatchSize  the default maximum number of rows in a multi-row insert statement, see WithBatchSize.
+	defaultBatchSize = 1000
+
+	// defaultStmtCacheSize  the default number of the cached prepared statements, see WithStmtCacheSize.
+	defaultStmtCacheSize = 64
 
 	// defaultPlaceholder .
 	defaultPlaceholder = "?"
+
+	// likeEscape  the escape character of the LIKE patterns, see Templates.Like.
+	likeEscape = '!'
+
+	// default column names.
+	defaultColumnID    = "id"
+	defaultColumnPType = "p_type"
+
+	// the audit timestamp column names, see WithTimestamps.
+	defaultColumnCreatedAt = "created_at"
+	defaultColumnUpdatedAt = "updated_at"
+
+	// defaultColumnDeletedAt  the soft delete column name, see WithSoftDelete.
+	defaultColumnDeletedAt = "deleted_at"
+
+	// index name prefixes.
+	indexPrefix       = "idx_"
+	uniqueIndexPrefix = "uk_"
+
+	// maxIdentifierLength  the shortest identifier length limit of the supported databases, it is PostgreSQL.
+	maxIdentifierLength = 63
+
+	// column types.
+	columnLengthPType = 32
+	columnLengthValue = 255
 )
 
-type adapterDriverNameIndex int
+// general SQL for all supported databases, they are the defaults of the optional Templates.
+const (
+	sqlTimestamp = "    %s TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL"
+	sqlDeletedAt = "    %s TIMESTAMP NULL"
+	sqlAddColumn = "ALTER TABLE %s ADD COLUMN %s"
+	sqlMatch     = "%s=?"
+	sqlNotMatch  = "%[1]s%[2]s"
+	// sqlLiveRows  the condition of the rules which are not soft deleted, %s is the deleted_at column.
+	sqlLiveRows = "%s IS NULL"
+	// sqlPartialIndex  the condition of the unique index for the rules which are not soft deleted.
+	sqlPartialIndex = " WHERE " + sqlLiveRows
+	// sqlPurgeDeleted  %[2]s is the deleted_at column, %[3]s is the cutoff time by the database clock.
+	sqlPurgeDeleted = "DELETE FROM %[1]s WHERE %[2]s < %[3]s"
+	// sqlCurrentTimestamp  the timestamps are always set by the database clock.
+	sqlCurrentTimestamp = "CURRENT_TIMESTAMP"
+	// sqlTableExist  %[1]s is the schema, it is a placeholder or the current schema function.
+	sqlTableExist   = "SELECT 1 FROM information_schema.tables WHERE table_schema=%[1]s AND table_name=?"
+	sqlInsertRow    = "INSERT INTO %[1]s (%[2]s) VALUES (%[3]s)"
+	sqlUpdateRow    = "UPDATE %[1]s SET %[2]s WHERE %[3]s"
+	sqlDeleteAll    = "DELETE FROM %s"
+	sqlDeleteRow    = "DELETE FROM %[1]s WHERE %[2]s"
+	sqlDeleteByArgs = "DELETE FROM %s WHERE %s=?"
+	sqlSelectAll    = "SELECT %[1]s FROM %[2]s"
+	sqlSelectWhere  = "SELECT %[1]s FROM %[2]s WHERE "
+	// sqlPageOrder  %[1]s is the id column, %[2]s is the Limit of the page size.
+	sqlPageOrder = " ORDER BY %[1]s%[2]s"
+	// sqlLimit  %[1]d is the number of rows.
+	sqlLimit = " LIMIT %[1]d"
+	// sqlLike  the escape character is likeEscape, it is not a backslash, which is special in the MySQL strings.
+	sqlLike = "%[1]s LIKE ? ESCAPE '!'"
+)
 
+// for the schema migrations.
 const (
-	_SQLite adapterDriverNameIndex = iota + 1
-	_MySQL
-	_PostgreSQL
-	_SQLServer
+	// migrationTableSuffix  the version table name is the policy table name with this suffix.
+	migrationTableSuffix = "_migrations"
+
+	sqlCreateMigrationTable = `
+CREATE TABLE IF NOT EXISTS %[1]s(
+    version     INTEGER      NOT NULL PRIMARY KEY,
+    description VARCHAR(255) DEFAULT '' NOT NULL,
+    applied_at  TIMESTAMP    DEFAULT CURRENT_TIMESTAMP NOT NULL
+);`
+	sqlSelectMigrations = "SELECT version FROM %s"
+	sqlInsertMigration  = "INSERT INTO %s (version,description) VALUES (?,?)"
+	sqlDeleteMigrations = "DELETE FROM %s"
+	// sqlInsertMigrationValues  the descriptions are defined in the package, they have no quotes.
+	sqlInsertMigrationValues = "INSERT INTO %s (version,description) VALUES (%d,'%s');"
+	sqlDeleteDuplicateRow    = "DELETE FROM %[1]s WHERE %[3]s NOT IN (SELECT MIN(%[3]s) FROM %[1]s GROUP BY %[2]s)"
+	sqlRenameTable           = "ALTER TABLE %s RENAME TO %s"
+	sqlDropTable             = "DROP TABLE %s"
+	sqlDropIndex             = "DROP INDEX IF EXISTS %s"
+	// sqlDropUniqueIndex  %[3]s is the index name qualified by the schema.
+	sqlDropUniqueIndex = "DROP INDEX IF EXISTS %[3]s"
+	// sqlCopyGroupedRows  %[3]s is the insert columns, %[4]s is the select list, %[5]s is the rule columns.
+	sqlCopyGroupedRows = "INSERT INTO %[1]s (%[3]s) SELECT %[4]s FROM %[2]s GROUP BY %[5]s"
+	// sqlCopyRows  %[3]s is the copied columns.
+	sqlCopyRows      = "INSERT INTO %[1]s (%[3]s) SELECT %[3]s FROM %[2]s"
+	sqlSetTimestamps = "UPDATE %[1]s SET %[2]s=CURRENT_TIMESTAMP,%[3]s=CURRENT_TIMESTAMP"
 )
 
-// general SQL for all supported databases.
+// for the dialect detection, see DetectDialect.
 const (
-	sqlCreateTable = `
-CREATE TABLE %[1]s(
-    p_type VARCHAR(32),
-    v0     VARCHAR(255),
-    v1     VARCHAR(255),
-    v2     VARCHAR(255),
-    v3     VARCHAR(255),
-    v4     VARCHAR(255),
-    v5     VARCHAR(255)
-);
-CREATE INDEX idx_%[1]s ON %[1]s (p_type,v0,v1);`
-	sqlTableExist   = "SELECT 1 FROM %s WHERE 1=0"
-	sqlInsertRow    = "INSERT INTO %s (p_type,v0,v1,v2,v3,v4,v5) VALUES (?,?,?,?,?,?,?)"
-	sqlUpdateRow    = "UPDATE %s SET p_type=?,v0=?,v1=?,v2=?,v3=?,v4=?,v5=? WHERE p_type=? AND v0=? AND v1=? AND v2=? AND v3=? AND v4=? AND v5=?"
-	sqlDeleteAll    = "DELETE FROM %s"
-	sqlDeleteRow    = "DELETE FROM %s WHERE p_type=? AND v0=? AND v1=? AND v2=? AND v3=? AND v4=? AND v5=?"
-	sqlDeleteByArgs = "DELETE FROM %s WHERE p_type=?"
-	sqlSelectAll    = "SELECT p_type,v0,v1,v2,v3,v4,v5 FROM %s"
-	sqlSelectWhere  = "SELECT p_type,v0,v1,v2,v3,v4,v5 FROM %s WHERE "
+	sqlVersion          = "SELECT VERSION()"
+	sqlVersionSQLite3   = "SELECT sqlite_version()"
+	sqlVersionSQLServer = "SELECT @@VERSION"
+	sqlVersionOracle    = "SELECT banner FROM v$version WHERE ROWNUM = 1"
+	sqlVersionMySQL     = "SELECT @@version_comment"
 )
 
 // for SQLite3.
 const (
 	sqlCreateTableSQLite3 = `
 CREATE TABLE IF NOT EXISTS %[1]s(
-    p_type VARCHAR(32)  DEFAULT '' NOT NULL,
-    v0     VARCHAR(255) DEFAULT '' NOT NULL,
-    v1     VARCHAR(255) DEFAULT '' NOT NULL,
-    v2     VARCHAR(255) DEFAULT '' NOT NULL,
-    v3     VARCHAR(255) DEFAULT '' NOT NULL,
-    v4     VARCHAR(255) DEFAULT '' NOT NULL,
-    v5     VARCHAR(255) DEFAULT '' NOT NULL,
-    CHECK (TYPEOF("p_type") = "text" AND
-           LENGTH("p_type") <= 32),
-    CHECK (TYPEOF("v0") = "text" AND
-           LENGTH("v0") <= 255),
-    CHECK (TYPEOF("v1") = "text" AND
-           LENGTH("v1") <= 255),
-    CHECK (TYPEOF("v2") = "text" AND
-           LENGTH("v2") <= 255),
-    CHECK (TYPEOF("v3") = "text" AND
-           LENGTH("v3") <= 255),
-    CHECK (TYPEOF("v4") = "text" AND
-           LENGTH("v4") <= 255),
-    CHECK (TYPEOF("v5") = "text" AND
-           LENGTH("v5") <= 255)
+%[2]s
 );
-CREATE INDEX IF NOT EXISTS idx_%[1]s ON %[1]s (p_type,v0,v1);`
-	sqlTruncateTableSQLite3 = "DROP TABLE IF EXISTS %[1]s;" + sqlCreateTableSQLite3
+CREATE INDEX IF NOT EXISTS %[4]s ON %[5]s (%[3]s);`
+	sqlColumnDefSQLite3   = "    %s VARCHAR(%d) DEFAULT '' NOT NULL"
+	sqlColumnCheckSQLite3 = `    CHECK (TYPEOF(%[1]s) = 'text' AND
+           LENGTH(%[1]s) <= %[2]d)`
+	sqlPrimaryKeySQLite3   = "    %s INTEGER PRIMARY KEY AUTOINCREMENT"
+	sqlUniqueIndexSQLite3  = "\nCREATE UNIQUE INDEX IF NOT EXISTS %[1]s ON %[3]s (%[2]s)%[4]s;"
+	sqlInsertIgnoreSQLite3 = "INSERT OR IGNORE INTO %[1]s (%[2]s) VALUES (%[3]s)"
+	// sqlTableExistSQLite3  SQLite has the sqlite_master table in each schema, %[2]s is the schema with a dot.
+	sqlTableExistSQLite3 = "SELECT 1 FROM %[2]ssqlite_master WHERE type='table' AND name=?"
+	// SQLite can not add a column with a non-constant default, the existing rows are updated after adding the column.
+	sqlAddTimestampSQLite3 = "ALTER TABLE %s ADD COLUMN %s TIMESTAMP"
+	// sqlCutoffSQLite3  ? is the age in seconds.
+	sqlCutoffSQLite3 = "datetime('now', '-' || ? || ' seconds')"
 )
 
 // for MySQL.
 const (
 	sqlCreateTableMySQL = `
 CREATE TABLE IF NOT EXISTS %[1]s(
-    p_type VARCHAR(32)  DEFAULT '' NOT NULL,
-    v0     VARCHAR(255) DEFAULT '' NOT NULL,
-    v1     VARCHAR(255) DEFAULT '' NOT NULL,
-    v2     VARCHAR(255) DEFAULT '' NOT NULL,
-    v3     VARCHAR(255) DEFAULT '' NOT NULL,
-    v4     VARCHAR(255) DEFAULT '' NOT NULL,
-    v5     VARCHAR(255) DEFAULT '' NOT NULL,
-    INDEX idx_%[1]s (p_type,v0,v1)
+%[2]s,
+    INDEX %[4]s (%[3]s)
 ) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;`
+	sqlColumnDefMySQL  = "    %s VARCHAR(%d) DEFAULT '' NOT NULL"
+	sqlPrimaryKeyMySQL = "    %s BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY"
+	sqlDeletedAtMySQL  = "    %s TIMESTAMP NULL DEFAULT NULL"
+	// InnoDB limits the index key to 3072 bytes, so the unique key is on the hash of the columns,
+	// %[2]s is the hash expression.
+	sqlUniqueKeyMySQL = `    rule_key BINARY(32) AS (%[2]s) STORED,
+    UNIQUE KEY %[1]s (rule_key)`
+	sqlRuleKeyMySQL = "UNHEX(SHA2(CONCAT_WS(CHAR(31),%s),256))"
+	// sqlLiveRuleKeyMySQL  MySQL has no partial index, the soft deleted rules have the NULL key.
+	sqlLiveRuleKeyMySQL   = "IF(%s IS NULL,%s,NULL)"
+	sqlInsertIgnoreMySQL  = "INSERT INTO %[1]s (%[2]s) VALUES (%[3]s) ON DUPLICATE KEY UPDATE %[5]s=%[5]s"
+	sqlCurrentSchemaMySQL = "DATABASE()"
+	sqlAddPrimaryKeyMySQL = "ALTER TABLE %s ADD COLUMN %s BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY FIRST"
+	// MySQL can not select from the same table in the DELETE subquery directly.
+	sqlDeleteDuplicateRowMySQL = "DELETE FROM %[1]s WHERE %[3]s NOT IN (SELECT %[3]s FROM (SELECT MIN(%[3]s) AS %[3]s FROM %[1]s GROUP BY %[2]s) AS t)"
+	sqlAddUniqueKeyMySQL       = `ALTER TABLE %[1]s
+    ADD COLUMN rule_key BINARY(32) AS (%[2]s) STORED,
+    ADD UNIQUE KEY %[3]s (rule_key)`
+	sqlDropUniqueKeyMySQL = "ALTER TABLE %[2]s DROP INDEX %[1]s, DROP COLUMN rule_key"
+	sqlCutoffMySQL        = "CURRENT_TIMESTAMP - INTERVAL ? SECOND"
 )
 
 // for PostgreSQL.
@@ -107,35 +184,108 @@ const (
 	sqlPlaceholderPostgreSQL = "$"
 	sqlCreateTablePostgreSQL = `
 CREATE TABLE IF NOT EXISTS %[1]s(
-    p_type VARCHAR(32)  DEFAULT '' NOT NULL,
-    v0     VARCHAR(255) DEFAULT '' NOT NULL,
-    v1     VARCHAR(255) DEFAULT '' NOT NULL,
-    v2     VARCHAR(255) DEFAULT '' NOT NULL,
-    v3     VARCHAR(255) DEFAULT '' NOT NULL,
-    v4     VARCHAR(255) DEFAULT '' NOT NULL,
-    v5     VARCHAR(255) DEFAULT '' NOT NULL
+%[2]s
 );
-CREATE INDEX IF NOT EXISTS idx_%[1]s ON %[1]s (p_type,v0,v1);`
-	sqlInsertRowPostgreSQL = "INSERT INTO %s (p_type,v0,v1,v2,v3,v4,v5) VALUES ($1,$2,$3,$4,$5,$6,$7)"
-	sqlUpdateRowPostgreSQL = "UPDATE %s SET p_type=$1,v0=$2,v1=$3,v2=$4,v3=$5,v4=$6,v5=$7 WHERE p_type=$8 AND v0=$9 AND v1=$10 AND v2=$11 AND v3=$12 AND v4=$13 AND v5=$14"
-	sqlDeleteRowPostgreSQL = "DELETE FROM %s WHERE p_type=$1 AND v0=$2 AND v1=$3 AND v2=$4 AND v3=$5 AND v4=$6 AND v5=$7"
+CREATE INDEX IF NOT EXISTS %[4]s ON %[5]s (%[3]s);`
+	sqlColumnDefPostgreSQL     = "    %s VARCHAR(%d) DEFAULT '' NOT NULL"
+	sqlPrimaryKeyPostgreSQL    = "    %s BIGSERIAL PRIMARY KEY"
+	sqlTimestampPostgreSQL     = "    %s TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL"
+	sqlDeletedAtPostgreSQL     = "    %s TIMESTAMP WITH TIME ZONE NULL"
+	sqlCutoffPostgreSQL        = "CURRENT_TIMESTAMP - CAST(? AS INTEGER) * INTERVAL '1 second'"
+	sqlUniqueIndexPostgreSQL   = "\nCREATE UNIQUE INDEX IF NOT EXISTS %[1]s ON %[3]s (%[2]s)%[4]s;"
+	sqlInsertIgnorePostgreSQL  = "INSERT INTO %[1]s (%[2]s) VALUES (%[3]s) ON CONFLICT DO NOTHING"
+	sqlCopyFromPostgreSQL      = "COPY %[1]s (%[2]s) FROM STDIN"
+	sqlCurrentSchemaPostgreSQL = "current_schema()"
+	sqlAddPrimaryKeyPostgreSQL = "ALTER TABLE %s ADD COLUMN %s BIGSERIAL PRIMARY KEY"
+	// sqlResolveTablePostgreSQL  the name is resolved by the search path.
+	sqlResolveTablePostgreSQL = "SELECT 1 WHERE to_regclass(?) IS NOT NULL"
 )
 
 // for SQLServer.
 const (
+	// maxParamsSQLServer  the limit is 2100, the statements with parameters are executed by sp_executesql,
+	// which takes 2 of them.
+	maxParamsSQLServer = 2098
+
 	sqlPlaceholderSQLServer = "@p"
 	sqlCreateTableSQLServer = `
 CREATE TABLE %[1]s(
-    p_type NVARCHAR(32)  DEFAULT '' NOT NULL,
-    v0     NVARCHAR(255) DEFAULT '' NOT NULL,
-    v1     NVARCHAR(255) DEFAULT '' NOT NULL,
-    v2     NVARCHAR(255) DEFAULT '' NOT NULL,
-    v3     NVARCHAR(255) DEFAULT '' NOT NULL,
-    v4     NVARCHAR(255) DEFAULT '' NOT NULL,
-    v5     NVARCHAR(255) DEFAULT '' NOT NULL
+%[2]s
 );
-CREATE INDEX idx_%[1]s ON %[1]s (p_type,v0,v1);`
-	sqlInsertRowSQLServer = "INSERT INTO %s (p_type,v0,v1,v2,v3,v4,v5) VALUES (@p1,@p2,@p3,@p4,@p5,@p6,@p7)"
-	sqlUpdateRowSQLServer = "UPDATE %s SET p_type=@p1,v0=@p2,v1=@p3,v2=@p4,v3=@p5,v4=@p6,v5=@p7 WHERE p_type=@p8 AND v0=@p9 AND v1=@p10 AND v2=@p11 AND v3=@p12 AND v4=@p13 AND v5=@p14"
-	sqlDeleteRowSQLServer = "DELETE FROM %s WHERE p_type=@p1 AND v0=@p2 AND v1=@p3 AND v2=@p4 AND v3=@p5 AND v4=@p6 AND v5=@p7"
+CREATE INDEX %[4]s ON %[5]s (%[3]s);`
+	sqlColumnDefSQLServer  = "    %s NVARCHAR(%d) DEFAULT '' NOT NULL"
+	sqlPrimaryKeySQLServer = "    %s BIGINT IDENTITY(1,1) PRIMARY KEY"
+	// SQL Server limits the nonclustered index key to 1700 bytes, so the unique index is on the hash of the columns,
+	// %[2]s is the hash expression.
+	sqlUniqueKeySQLServer    = "    rule_key AS (%[2]s) PERSISTED"
+	sqlUniqueIndexSQLServer  = "\nCREATE UNIQUE INDEX %[1]s ON %[3]s (rule_key)%[4]s;"
+	sqlRuleKeySQLServer      = "HASHBYTES('SHA2_256',CONCAT_WS(NCHAR(31),%s))"
+	sqlAddRuleKeySQLServer   = "ALTER TABLE %[1]s ADD rule_key AS (%[2]s) PERSISTED"
+	sqlTimestampSQLServer    = "    %s DATETIME2 DEFAULT CURRENT_TIMESTAMP NOT NULL"
+	sqlDeletedAtSQLServer    = "    %s DATETIME2 NULL"
+	sqlAddColumnSQLServer    = "ALTER TABLE %s ADD %s"
+	sqlDropIndexSQLServer    = "DROP INDEX %[1]s ON %[2]s"
+	sqlCutoffSQLServer       = "DATEADD(SECOND, -?, CURRENT_TIMESTAMP)"
+	sqlInsertIgnoreSQLServer = "INSERT INTO %[1]s (%[2]s) SELECT %[3]s WHERE NOT EXISTS (SELECT 1 FROM %[1]s WITH (UPDLOCK, HOLDLOCK) WHERE %[4]s)"
+	// sqlTableExistSQLServer  %[1]s is the schema id, SCHEMA_ID() returns the default schema id.
+	// sqlResolveTableSQLServer  the name is resolved in the default schema, then in dbo.
+	sqlTableExistSQLServer           = "SELECT 1 FROM sys.tables WHERE schema_id=%[1]s AND name=?"
+	sqlCurrentSchemaSQLServer        = "SCHEMA_ID()"
+	sqlResolveTableSQLServer         = "SELECT 1 WHERE OBJECT_ID(?, 'U') IS NOT NULL"
+	sqlSchemaSQLServer               = "SCHEMA_ID(?)"
+	sqlAddPrimaryKeySQLServer        = "ALTER TABLE %s ADD %s BIGINT IDENTITY(1,1) PRIMARY KEY"
+	sqlLimitSQLServer                = " OFFSET 0 ROWS FETCH NEXT %[1]d ROWS ONLY"
+	sqlLikeEscapesSQLServer          = "["
+	sqlCreateMigrationTableSQLServer = `
+IF OBJECT_ID(N'%[1]s', N'U') IS NULL
+CREATE TABLE %[1]s(
+    version     INT           NOT NULL PRIMARY KEY,
+    description NVARCHAR(255) DEFAULT '' NOT NULL,
+    applied_at  DATETIME      DEFAULT CURRENT_TIMESTAMP NOT NULL
+);`
+)
+
+// for Oracle, it requires Oracle Database 12c or later.
+// Oracle regards the empty string as NULL, so the rule columns are nullable.
+const (
+	sqlPlaceholderOracle = ":"
+	// Oracle can not execute multiple statements, so the DDL statements are in a PL/SQL block.
+	sqlBlockOracle       = "BEGIN\n%s\nEND;"
+	sqlCreateTableOracle = `    EXECUTE IMMEDIATE 'CREATE TABLE %[1]s(
+%[2]s
+)';
+    EXECUTE IMMEDIATE 'CREATE INDEX %[4]s ON %[5]s (%[3]s)';`
+	sqlColumnDefOracle  = "    %s VARCHAR2(%d)"
+	sqlPrimaryKeyOracle = "    %s NUMBER(19) GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY"
+	// Oracle has no partial index, the soft deleted rules have the NULL keys which are not indexed.
+	sqlUniqueIndexOracle = "\n    EXECUTE IMMEDIATE 'CREATE UNIQUE INDEX %[1]s ON %[3]s (%[2]s)';"
+	sqlLiveColumnOracle  = "CASE WHEN %s IS NULL THEN %s END"
+	sqlAddColumnOracle   = "ALTER TABLE %s ADD %s"
+	// sqlMatchOracle  DECODE regards two NULLs as equal, so the empty values can be matched.
+	sqlMatchOracle = "DECODE(%s,?,1)=1"
+	// sqlNotMatchOracle  the empty values are NULL, they do not equal the values.
+	sqlNotMatchOracle = "(%[1]s IS NULL OR %[1]s%[2]s)"
+	// sqlInsertIgnoreOracle  %[6]s is the table name without schema, %[7]s is the unique index name.
+	sqlInsertIgnoreOracle = "INSERT /*+ IGNORE_ROW_ON_DUPKEY_INDEX(%[6]s, %[7]s) */ INTO %[1]s (%[2]s) VALUES (%[3]s)"
+	// sqlTableExistOracle  %[1]s is the owner, it is a placeholder or the current schema.
+	sqlTableExistOracle           = "SELECT 1 FROM all_tables WHERE owner=%[1]s AND table_name=?"
+	sqlCurrentSchemaOracle        = "SYS_CONTEXT('USERENV','CURRENT_SCHEMA')"
+	sqlAddPrimaryKeyOracle        = "ALTER TABLE %s ADD %s NUMBER(19) GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY"
+	sqlDropIndexOracle            = "DROP INDEX %[3]s"
+	sqlLimitOracle                = " FETCH FIRST %[1]d ROWS ONLY"
+	sqlCutoffOracle               = "CURRENT_TIMESTAMP - NUMTODSINTERVAL(?, 'SECOND')"
+	sqlCreateMigrationTableOracle = `
+BEGIN
+    EXECUTE IMMEDIATE 'CREATE TABLE %[1]s(
+    version     NUMBER(10)    NOT NULL PRIMARY KEY,
+    description VARCHAR2(255),
+    applied_at  TIMESTAMP     DEFAULT CURRENT_TIMESTAMP NOT NULL
+)';
+EXCEPTION
+    WHEN OTHERS THEN
+        -- ORA-00955: the table exists already.
+        IF SQLCODE != -955 THEN
+            RAISE;
+        END IF;
+END;`
 )
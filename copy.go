@@ -0,0 +1,153 @@
+// Copyright 2026 by Blank-Xu. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqladapter
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	readerType  = reflect.TypeOf((*io.Reader)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// copyTextReplacer  escapes the values in the text format of COPY.
+var copyTextReplacer = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// copyPgConnTx execute the delete statement and load the rows by copyPgConn in one transaction,
+// check is called before the commit if it is not nil.
+// The transaction and the COPY use the same connection of db by sql.Conn.
+func (d dao) copyPgConnTx(ctx context.Context, db *sql.DB, deleteData txData, rules [][]interface{},
+	check func(ctx context.Context, tx DBTX) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx err: %w", err)
+	}
+
+	var step string
+
+	if _, err = d.exec(ctx, tx, deleteData.query, deleteData.args...); err != nil {
+		step = deleteData.step + " before copy"
+		goto ROLLBACK
+	}
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		return copyPgConn(ctx, driverConn, d.sqlCopyFrom, rules)
+	})
+	if err != nil {
+		step = "copy from"
+		goto ROLLBACK
+	}
+
+	if check != nil {
+		if err = check(ctx, tx); err != nil {
+			step = "check rows"
+			goto ROLLBACK
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		step = "commit"
+		goto ROLLBACK
+	}
+
+	return nil
+
+ROLLBACK:
+
+	if err1 := tx.Rollback(); err1 != nil {
+		return d.wrapError(fmt.Errorf("%s err: %w, rollback err: %w", step, err, err1))
+	}
+
+	return d.wrapError(fmt.Errorf("%s err: %w", step, err))
+}
+
+// copyPgConn load the rows by pgconn.PgConn.CopyFrom of the driver connection of pgx,
+// the driver connection has Conn() *pgx.Conn, and the pgx.Conn has PgConn() *pgconn.PgConn.
+// The methods are called by reflect, so the drivers are not imported, pgx v4 and v5 have the same methods.
+func copyPgConn(ctx context.Context, driverConn interface{}, query string, rows [][]interface{}) error {
+	value := reflect.ValueOf(driverConn)
+
+	for _, name := range []string{"Conn", "PgConn"} {
+		method := value.MethodByName(name)
+		if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+			return fmt.Errorf("the driver connection %T has no pgconn.PgConn", driverConn)
+		}
+
+		if value = method.Call(nil)[0]; value.Kind() == reflect.Ptr && value.IsNil() {
+			return fmt.Errorf("the driver connection %T has no pgconn.PgConn", driverConn)
+		}
+	}
+
+	copyFrom := value.MethodByName("CopyFrom")
+	if !copyFrom.IsValid() {
+		return fmt.Errorf("the connection %s has no CopyFrom", value.Type())
+	}
+
+	t := copyFrom.Type()
+	if t.NumIn() != 3 || t.In(0) != contextType || t.In(1) != readerType || t.In(2).Kind() != reflect.String ||
+		t.NumOut() != 2 || t.Out(1) != errorType {
+		return fmt.Errorf("the connection %s has an unsupported CopyFrom: %s", value.Type(), t)
+	}
+
+	reader := io.Reader(&copyReader{rows: rows})
+
+	result := copyFrom.Call([]reflect.Value{reflect.ValueOf(&ctx).Elem(), reflect.ValueOf(&reader).Elem(), reflect.ValueOf(query)})
+	if err, _ := result[1].Interface().(error); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// copyReader  reads the rows in the text format of COPY, a row per line and the values are separated by tabs.
+type copyReader struct {
+	rows [][]interface{}
+	buf  bytes.Buffer
+}
+
+func (r *copyReader) Read(p []byte) (int, error) {
+	for r.buf.Len() == 0 {
+		if len(r.rows) == 0 {
+			return 0, io.EOF
+		}
+
+		for idx, value := range r.rows[0] {
+			if idx != 0 {
+				r.buf.WriteByte('\t')
+			}
+
+			_, _ = copyTextReplacer.WriteString(&r.buf, fmt.Sprint(value))
+		}
+
+		r.buf.WriteByte('\n')
+		r.rows = r.rows[1:]
+	}
+
+	return r.buf.Read(p)
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 by Blank-Xu. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqladapter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+// testPgxConn  the driver connection of pgx stdlib, Conn returns the pgx.Conn.
+type testPgxConn struct {
+	conn *testPgxPgConn
+}
+
+func (c testPgxConn) Conn() *testPgxPgConn {
+	return c.conn
+}
+
+type testPgxPgConn struct {
+	pgConn *testPgConn
+}
+
+func (c *testPgxPgConn) PgConn() *testPgConn {
+	return c.pgConn
+}
+
+type testCommandTag string
+
+// testPgConn  records the COPY of pgconn.PgConn.CopyFrom.
+type testPgConn struct {
+	query string
+	data  string
+	err   error
+}
+
+func (c *testPgConn) CopyFrom(_ context.Context, r io.Reader, sql string) (testCommandTag, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	c.query, c.data = sql, string(data)
+
+	return "COPY", c.err
+}
+
+func TestCopyPgConn(t *testing.T) {
+	query := `COPY "casbin_rule" ("ptype","v0","v1") FROM STDIN`
+	rows := [][]interface{}{
+		{"p", "alice", "data1"},
+		{"p", "", `a\b`},
+		{"g", "tab\tline\nreturn\r", "bob"},
+	}
+
+	pgConn := &testPgConn{}
+	if err := copyPgConn(context.Background(), testPgxConn{conn: &testPgxPgConn{pgConn: pgConn}}, query, rows); err != nil {
+		t.Fatalf("copy err: %s", err)
+	}
+
+	want := "p\talice\tdata1\n" +
+		"p\t\ta\\\\b\n" +
+		"g\ttab\\tline\\nreturn\\r\tbob\n"
+	if pgConn.data != want {
+		t.Errorf("test case[data] failed, got: %q, want: %q", pgConn.data, want)
+	}
+	if pgConn.query != query {
+		t.Errorf("test case[query] failed, got: %s, want: %s", pgConn.query, query)
+	}
+
+	copyErr := errors.New("copy failed")
+	pgConn = &testPgConn{err: copyErr}
+	if err := copyPgConn(context.Background(), testPgxConn{conn: &testPgxPgConn{pgConn: pgConn}}, query, rows); !errors.Is(err, copyErr) {
+		t.Errorf("test case[copy error] failed, got: %v, want: %s", err, copyErr)
+	}
+
+	if err := copyPgConn(context.Background(), testPgxConn{}, query, rows); err == nil {
+		t.Error("test case[nil conn] failed, got: nil, want: error")
+	}
+
+	if err := copyPgConn(context.Background(), versionConn{}, query, rows); err == nil {
+		t.Error("test case[unsupported conn] failed, got: nil, want: error")
+	}
+}
@@ -18,78 +18,462 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 )
 
-func newDao(db *sql.DB, driverNameIndex adapterDriverNameIndex, tableName string) dao {
+func newDao(db DBTX, dialect Dialect, tableName string, opts options) dao {
 	d := dao{
-		db: db,
+		db:     db,
+		readDB: opts.readDB,
+
+		dialect:          dialect,
+		templates:        dialect.Templates().withDefaults(),
+		schema:           opts.schema,
+		tableName:        tableName,
+		uniqueIndex:      opts.uniqueIndex,
+		ignoreDuplicates: opts.ignoreDuplicates,
+		batchSize:        opts.batchSize,
+		primaryKey:       opts.uniqueIndex || opts.pageSize > 0,
+		pageSize:         opts.pageSize,
+		snapshot:         opts.snapshot,
+		timestamps:       opts.timestamps,
+		softDelete:       opts.softDelete,
+		diffSave:         opts.diffSave,
+	}
+
+	if d.readDB == nil {
+		d.readDB = db
+	}
+
+	t := d.templates
+
+	d.table = d.qualify(tableName)
+	d.migrationTable = d.qualify(tableName + migrationTableSuffix)
+	d.idColumn = d.quote(opts.columnName(defaultColumnID))
+
+	for _, column := range opts.columnNames() {
+		d.columns = append(d.columns, d.quote(column))
+	}
+
+	// the optional columns are always named, the migrations may need them.
+	d.createdAtColumn = d.quote(opts.columnName(defaultColumnCreatedAt))
+	d.updatedAtColumn = d.quote(opts.columnName(defaultColumnUpdatedAt))
+	d.deletedAtColumn = d.quote(opts.columnName(defaultColumnDeletedAt))
+
+	columns := d.columns
+	columnList := strings.Join(columns, ",")
+
+	matches := make([]string, len(columns))
+	for idx, column := range columns {
+		matches[idx] = fmt.Sprintf(t.Match, column)
+	}
+
+	matchList := strings.Join(matches, " AND ")
+
+	// the insert and update SQL set the timestamps by the database clock.
+	insertList := columnList
+	insertValues := genPlaceholders(len(columns))
+	updateList := strings.Join(columns, "=?,") + "=?"
+	selectList := columnList
+
+	if d.timestamps {
+		insertList += "," + d.createdAtColumn + "," + d.updatedAtColumn
+		insertValues += "," + sqlCurrentTimestamp + "," + sqlCurrentTimestamp
+		updateList += "," + d.updatedAtColumn + "=" + sqlCurrentTimestamp
+		selectList = insertList
+	}
+
+	// the soft deleted rules are excluded from all the SQL except insert.
+	liveMatchList := matchList
+	deleteSet := d.deletedAtColumn + "=" + sqlCurrentTimestamp
+	liveCondition := fmt.Sprintf(sqlLiveRows, d.deletedAtColumn)
 
-		tableName:   tableName,
-		placeHolder: defaultPlaceholder,
+	if d.softDelete {
+		liveMatchList += " AND " + liveCondition
 
-		sqlCreateTable: fmt.Sprintf(sqlCreateTable, tableName),
+		if d.timestamps {
+			deleteSet += "," + d.updatedAtColumn + "=" + sqlCurrentTimestamp
+		}
+	}
+
+	d.sqlInsertRow = fmt.Sprintf(t.InsertRow, d.table, insertList, insertValues)
+	d.sqlUpdateRow = fmt.Sprintf(t.UpdateRow, d.table, updateList, liveMatchList)
+	d.sqlDeleteAll = fmt.Sprintf(sqlDeleteAll, d.table)
+	d.sqlDeleteRow = fmt.Sprintf(t.DeleteRow, d.table, matchList)
+	d.sqlDeleteByArgs = fmt.Sprintf(sqlDeleteByArgs, d.table, columns[0])
+	d.sqlDeleteWhere = fmt.Sprintf(t.DeleteRow, d.table, "")
+
+	d.sqlSelectAll = fmt.Sprintf(t.SelectAll, columnList, d.table)
+	d.sqlSelectWhere = fmt.Sprintf(t.SelectWhere, columnList, d.table)
+	d.sqlSelectAudited = fmt.Sprintf(t.SelectAll, selectList, d.table)
+	d.sqlSelectAuditedWhere = fmt.Sprintf(t.SelectWhere, selectList, d.table)
+	d.sqlSelectPage = fmt.Sprintf(t.SelectAll, columnList+","+d.idColumn, d.table)
+	d.sqlSelectPageWhere = fmt.Sprintf(t.SelectWhere, columnList+","+d.idColumn, d.table)
+	d.sqlPageOrder = fmt.Sprintf(sqlPageOrder, d.idColumn, fmt.Sprintf(t.Limit, d.pageSize))
+
+	if d.softDelete {
+		d.sqlDeleteAll = fmt.Sprintf(t.UpdateRow, d.table, deleteSet, liveCondition)
+		d.sqlDeleteRow = fmt.Sprintf(t.UpdateRow, d.table, deleteSet, liveMatchList)
+		d.sqlDeleteByArgs = fmt.Sprintf(t.UpdateRow, d.table, deleteSet, liveCondition+" AND "+columns[0]+"=?")
+		d.sqlDeleteWhere = fmt.Sprintf(t.UpdateRow, d.table, deleteSet, liveCondition+" AND ")
+
+		d.sqlSelectAll += " WHERE " + liveCondition
+		d.sqlSelectWhere += liveCondition + " AND "
+		d.sqlSelectAudited += " WHERE " + liveCondition
+		d.sqlSelectAuditedWhere += liveCondition + " AND "
+		d.sqlSelectPage += " WHERE " + liveCondition
+		d.sqlSelectPageWhere += liveCondition + " AND "
+	}
+
+	d.sqlCreateMigrationTable = fmt.Sprintf(t.CreateMigrationTable, d.migrationTable)
+	d.sqlSelectMigrations = fmt.Sprintf(sqlSelectMigrations, d.migrationTable)
+	d.sqlInsertMigration = fmt.Sprintf(sqlInsertMigration, d.migrationTable)
+	d.sqlDeleteMigrations = fmt.Sprintf(sqlDeleteMigrations, d.migrationTable)
+
+	// the schema placeholder is before the table name placeholder.
+	d.tableExistArgs = []interface{}{d.fold(tableName)}
+	schemaArg, schemaPrefix := t.CurrentSchema, ""
+
+	if d.schema != "" {
+		if t.Schema != "" {
+			schemaArg = t.Schema
+			d.tableExistArgs = []interface{}{d.fold(d.schema), d.fold(tableName)}
+		} else {
+			schemaPrefix = d.quote(d.schema) + "."
+		}
+	}
+
+	d.sqlTableExist = fmt.Sprintf(t.TableExist, schemaArg, schemaPrefix)
+
+	// the version table is checked by the same query with its name.
+	d.migrationTableExistArgs = append([]interface{}{}, d.tableExistArgs...)
+	d.migrationTableExistArgs[len(d.migrationTableExistArgs)-1] = d.fold(tableName + migrationTableSuffix)
 
-		sqlTableExist: fmt.Sprintf(sqlTableExist, tableName),
+	// the unqualified names are resolved as the other statements resolve them.
+	if d.schema == "" && t.ResolveTable != "" {
+		d.sqlTableExist = t.ResolveTable
+		d.tableExistArgs = []interface{}{d.quote(tableName)}
+		d.migrationTableExistArgs = []interface{}{d.quote(tableName + migrationTableSuffix)}
+	}
+
+	d.sqlCreateTable = d.genCreateTableSQL()
+
+	if t.Cutoff != "" {
+		d.sqlPurgeDeleted = fmt.Sprintf(sqlPurgeDeleted, d.table, d.deletedAtColumn, t.Cutoff)
+	}
 
-		sqlInsertRow:    fmt.Sprintf(sqlInsertRow, tableName),
-		sqlUpdateRow:    fmt.Sprintf(sqlUpdateRow, tableName),
-		sqlDeleteAll:    fmt.Sprintf(sqlDeleteAll, tableName),
-		sqlDeleteRow:    fmt.Sprintf(sqlDeleteRow, tableName),
-		sqlDeleteByArgs: fmt.Sprintf(sqlDeleteByArgs, tableName),
+	if t.CopyFrom != "" {
+		d.sqlCopyFrom = fmt.Sprintf(t.CopyFrom, d.table, columnList)
+	}
 
-		sqlSelectAll:   fmt.Sprintf(sqlSelectAll, tableName),
-		sqlSelectWhere: fmt.Sprintf(sqlSelectWhere, tableName),
+	// sqlInsertBatch  the values of the rows replace the verb later.
+	if t.MultiRowInsert {
+		d.sqlInsertBatch = fmt.Sprintf(t.InsertRow, d.table, insertList, "%s")
 	}
 
-	switch driverNameIndex {
-	case _SQLite:
-		d.sqlCreateTable = fmt.Sprintf(sqlCreateTableSQLite3, tableName)
-	case _MySQL:
-		d.sqlCreateTable = fmt.Sprintf(sqlCreateTableMySQL, tableName)
-	case _PostgreSQL:
-		d.placeHolder = sqlPlaceholderPostgreSQL
-		d.sqlCreateTable = fmt.Sprintf(sqlCreateTablePostgreSQL, tableName)
-		d.sqlInsertRow = fmt.Sprintf(sqlInsertRowPostgreSQL, tableName)
-		d.sqlUpdateRow = fmt.Sprintf(sqlUpdateRowPostgreSQL, tableName)
-		d.sqlDeleteRow = fmt.Sprintf(sqlDeleteRowPostgreSQL, tableName)
-	case _SQLServer:
-		d.placeHolder = sqlPlaceholderSQLServer
-		d.sqlCreateTable = fmt.Sprintf(sqlCreateTableSQLServer, tableName)
-		d.sqlInsertRow = fmt.Sprintf(sqlInsertRowSQLServer, tableName)
-		d.sqlUpdateRow = fmt.Sprintf(sqlUpdateRowSQLServer, tableName)
-		d.sqlDeleteRow = fmt.Sprintf(sqlDeleteRowSQLServer, tableName)
+	if opts.ignoreDuplicates && t.InsertIgnore != "" {
+		d.sqlInsertRow = fmt.Sprintf(t.InsertIgnore, d.table, insertList, d.rebindSQL(insertValues), d.rebindSQL(liveMatchList),
+			columns[0], d.quote(tableName), d.quote(uniqueIndexPrefix+tableName))
+
+		d.sqlInsertBatch = ""
+		if t.MultiRowInsertIgnore {
+			d.sqlInsertBatch = fmt.Sprintf(t.InsertIgnore, d.table, insertList, "%s", d.rebindSQL(liveMatchList),
+				columns[0], d.quote(tableName), d.quote(uniqueIndexPrefix+tableName))
+		}
 	}
 
+	d.insertValues = insertValues
+
+	// the fixed SQL only need to rebind once.
+	d.sqlInsertRow = d.rebindSQL(d.sqlInsertRow)
+	d.sqlUpdateRow = d.rebindSQL(d.sqlUpdateRow)
+	d.sqlDeleteRow = d.rebindSQL(d.sqlDeleteRow)
+	d.sqlInsertMigration = d.rebindSQL(d.sqlInsertMigration)
+	d.sqlTableExist = d.rebindSQL(d.sqlTableExist)
+	d.sqlPurgeDeleted = d.rebindSQL(d.sqlPurgeDeleted)
+
 	return d
 }
 
+// quote quote the identifier by the database.
+func (d dao) quote(name string) string {
+	return d.dialect.Quote(d.fold(name))
+}
+
+// fold returns the name in the case of the database catalog, see Templates.UpperQuoted.
+func (d dao) fold(name string) string {
+	if d.templates.UpperQuoted {
+		return strings.ToUpper(name)
+	}
+
+	return name
+}
+
+// qualify returns the quoted name qualified by the schema.
+func (d dao) qualify(name string) string {
+	if d.schema == "" {
+		return d.quote(name)
+	}
+
+	return d.quote(d.schema) + "." + d.quote(name)
+}
+
+// indexName returns the index name of the table with the prefix.
+// E.g. SQLite and Oracle qualify the index name by the schema.
+func (d dao) indexName(prefix string) string {
+	if d.templates.QualifyIndex {
+		return d.qualify(prefix + d.tableName)
+	}
+
+	return d.quote(prefix + d.tableName)
+}
+
+// indexTable returns the table name in the create index SQL.
+func (d dao) indexTable() string {
+	if d.templates.UnqualifiedIndexTable {
+		return d.quote(d.tableName)
+	}
+
+	return d.table
+}
+
+// genCreateTableSQL generate the create table SQL.
+// The index is created on p_type and the first two value columns,
+// if the dao has the unique index, the table has a surrogate primary key and a unique index on all the columns.
+func (d dao) genCreateTableSQL() string {
+	t := d.templates
+	columns := d.columns
+	defs := make([]string, 0, len(columns)*2+2)
+	checks := make([]string, 0, len(columns))
+
+	if d.primaryKey {
+		defs = append(defs, fmt.Sprintf(t.PrimaryKey, d.idColumn))
+	}
+
+	for idx, column := range columns {
+		length := columnLengthValue
+		if idx == 0 {
+			length = columnLengthPType
+		}
+
+		defs = append(defs, fmt.Sprintf(t.Column, column, length))
+
+		if t.Check != "" {
+			checks = append(checks, fmt.Sprintf(t.Check, column, length))
+		}
+	}
+
+	if d.timestamps {
+		defs = append(defs, fmt.Sprintf(t.Timestamp, d.createdAtColumn), fmt.Sprintf(t.Timestamp, d.updatedAtColumn))
+	}
+
+	if d.softDelete {
+		defs = append(defs, fmt.Sprintf(t.DeletedAt, d.deletedAtColumn))
+	}
+
+	defs = append(defs, checks...)
+
+	uniqueIndexName := d.indexName(uniqueIndexPrefix)
+
+	if d.uniqueIndex && t.UniqueKey != "" {
+		defs = append(defs, fmt.Sprintf(t.UniqueKey, uniqueIndexName, d.uniqueTarget(), d.indexTable()))
+	}
+
+	indexColumns := columns
+	if len(indexColumns) > 3 {
+		indexColumns = indexColumns[:3]
+	}
+
+	query := fmt.Sprintf(t.CreateTable, d.table, strings.Join(defs, ",\n"), strings.Join(indexColumns, ","),
+		d.indexName(indexPrefix), d.indexTable())
+
+	if d.uniqueIndex && t.UniqueIndex != "" {
+		query += fmt.Sprintf(t.UniqueIndex, uniqueIndexName, d.uniqueTarget(), d.indexTable(), d.uniqueCondition())
+	}
+
+	if t.Block != "" {
+		query = fmt.Sprintf(t.Block, query)
+	}
+
+	return query
+}
+
+// uniqueTarget returns the columns or the RuleKey expression of the unique index.
+// The soft deleted rules are not unique.
+func (d dao) uniqueTarget() string {
+	t := d.templates
+	columnList := strings.Join(d.columns, ",")
+
+	if t.RuleKey != "" {
+		target := fmt.Sprintf(t.RuleKey, columnList)
+		if d.softDelete && t.LiveKey != "" {
+			target = fmt.Sprintf(t.LiveKey, d.deletedAtColumn, target)
+		}
+
+		return target
+	}
+
+	if !d.softDelete || t.LiveKey == "" {
+		return columnList
+	}
+
+	targets := make([]string, len(d.columns))
+	for idx, column := range d.columns {
+		targets[idx] = fmt.Sprintf(t.LiveKey, d.deletedAtColumn, column)
+	}
+
+	return strings.Join(targets, ",")
+}
+
+// uniqueCondition returns the condition of the partial unique index with the soft delete.
+func (d dao) uniqueCondition() string {
+	if !d.softDelete || d.templates.PartialIndex == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(d.templates.PartialIndex, d.deletedAtColumn)
+}
+
+// addUniqueIndexSQL returns the SQL to add the unique index to the existing table.
+func (d dao) addUniqueIndexSQL() string {
+	t := d.templates
+	uniqueIndexName := d.indexName(uniqueIndexPrefix)
+
+	if t.AddUniqueIndex != "" {
+		return fmt.Sprintf(t.AddUniqueIndex, d.table, d.uniqueTarget(), uniqueIndexName)
+	}
+
+	query := fmt.Sprintf(strings.TrimSpace(t.UniqueIndex), uniqueIndexName, d.uniqueTarget(), d.indexTable(), d.uniqueCondition())
+	if t.Block != "" {
+		query = fmt.Sprintf(t.Block, query)
+	}
+
+	return query
+}
+
+// dropUniqueIndexSQL returns the SQL to drop the unique index.
+func (d dao) dropUniqueIndexSQL() string {
+	return fmt.Sprintf(d.templates.DropUniqueIndex, d.indexName(uniqueIndexPrefix), d.table, d.qualify(uniqueIndexPrefix+d.tableName))
+}
+
+// genPlaceholders generate count placeholders separated by comma.
+func genPlaceholders(count int) string {
+	return strings.TrimSuffix(strings.Repeat(defaultPlaceholder+",", count), ",")
+}
+
 type dao struct {
-	db *sql.DB
+	// db  the executor of the SQL, *sql.DB by default, see Adapter.WithTx.
+	db DBTX
+
+	// stmts  the prepared statements of the fixed queries and the filter shapes, it is nil if disabled.
+	stmts *stmtCache
+
+	// readDB  the executor of the policy loads, it is db by default, see WithReadDB.
+	readDB DBTX
 
+	// dialect  the SQL of the database.
+	dialect Dialect
+
+	// templates  the SQL templates of the dialect, with the defaults.
+	templates Templates
+
+	// schema  the optional schema name.
+	schema string
+
+	// tableName  the table name without schema.
 	tableName string
 
-	placeHolder string
+	// table  the quoted table name qualified by the schema, it is used in SQL.
+	table string
+
+	// idColumn  the quoted surrogate primary key column name.
+	idColumn string
+
+	// columns  the quoted column names of the table, columns[0] is p_type.
+	columns []string
+
+	// primaryKey  the table has the surrogate primary key idColumn.
+	primaryKey bool
+
+	// uniqueIndex  the table has a surrogate primary key and a unique index.
+	uniqueIndex bool
+
+	// ignoreDuplicates  the insert SQL skips the duplicate rules.
+	ignoreDuplicates bool
+
+	// batchSize  the maximum number of rows in a multi-row insert statement.
+	batchSize int
+
+	// pageSize  the number of rows in a page of the loads, see WithPageSize.
+	pageSize int
+	// snapshot  the pages are read in one transaction, see WithSnapshot.
+	snapshot bool
+
+	// timestamps  the table has the audit timestamp columns, they are quoted.
+	timestamps      bool
+	createdAtColumn string
+	updatedAtColumn string
+
+	// softDelete  the removed rules are marked by the quoted deleted_at column.
+	softDelete      bool
+	deletedAtColumn string
+
+	// migrationTable  the quoted version table of the schema migrations.
+	migrationTable string
 
 	sqlCreateTable string
 
-	sqlTableExist  string
+	sqlCreateMigrationTable string
+	sqlSelectMigrations     string
+	sqlInsertMigration      string
+	sqlDeleteMigrations     string
+
+	sqlTableExist           string
+	tableExistArgs          []interface{}
+	migrationTableExistArgs []interface{}
+
 	sqlSelectAll   string
 	sqlSelectWhere string
 
+	// sqlSelectPage and sqlSelectPageWhere  select the rule columns and the id column,
+	// sqlPageOrder  orders and limits the page.
+	sqlSelectPage      string
+	sqlSelectPageWhere string
+	sqlPageOrder       string
+
+	sqlSelectAudited      string
+	sqlSelectAuditedWhere string
+
 	sqlInsertRow string
 	sqlUpdateRow string
 
+	// sqlInsertBatch  the multi-row insert SQL without binding, "%s" is the values of the rows,
+	// it is empty if the database does not support.
+	sqlInsertBatch string
+	// insertValues  the values of a row in the insert SQL without binding.
+	insertValues string
+
+	// copyFrom  SavePolicy loads the rules by sqlCopyFrom, see WithCopyFrom.
+	copyFrom    copyFromMethod
+	sqlCopyFrom string
+
+	// diffSave  SavePolicy only applies the difference of the rules, see WithDiffSave.
+	diffSave bool
+
 	sqlDeleteAll    string
 	sqlDeleteRow    string
 	sqlDeleteByArgs string
+	// sqlDeleteWhere  the delete SQL without the filter condition.
+	sqlDeleteWhere string
+
+	sqlPurgeDeleted string
 }
 
 // rebindSQL rebind SQL by different database.
 func (d dao) rebindSQL(query string) string {
-	if d.placeHolder == defaultPlaceholder {
+	if d.dialect.Placeholder(1) == defaultPlaceholder {
 		return query
 	}
 
@@ -106,8 +490,7 @@ func (d dao) rebindSQL(query string) string {
 		num++
 
 		result = append(result, query[:idx]...)
-		result = append(result, d.placeHolder...)
-		result = strconv.AppendInt(result, int64(num), 10)
+		result = append(result, d.dialect.Placeholder(num)...)
 
 		query = query[idx+1:]
 	}
@@ -115,90 +498,287 @@ func (d dao) rebindSQL(query string) string {
 	return string(append(result, query...))
 }
 
+// readFromPrimaryKey  the context key of ReadFromPrimary.
+type readFromPrimaryKey struct{}
+
+// ReadFromPrimary  returns a context to load the policy rules from the primary db instead of the db of WithReadDB,
+// e.g. to read the rules right after SavePolicy, before they are replicated.
+func ReadFromPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readFromPrimaryKey{}, true)
+}
+
+// reader returns the executor of the policy loads.
+func (d dao) reader(ctx context.Context) DBTX {
+	if primary, _ := ctx.Value(readFromPrimaryKey{}).(bool); primary {
+		return d.db
+	}
+
+	return d.readDB
+}
+
 // querySQL query data by sql.
 func (d dao) querySQL(ctx context.Context, query string, args ...interface{}) ([]rule, error) {
-	rows, err := d.db.QueryContext(ctx, query, args...)
+	rules := make([]rule, 0, 128)
+
+	err := d.queryEach(ctx, query, args, func(line rule) error {
+		rules = append(rules, line)
+
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	rules := make([]rule, 0, 128)
+	return rules, nil
+}
 
-	for rows.Next() {
-		var rule rule
+// queryEach query data by sql, and call fn with each row without collecting the rows.
+// It stops and returns the error of fn.
+func (d dao) queryEach(ctx context.Context, query string, args []interface{}, fn func(line rule) error) error {
+	rows, release, err := d.query(ctx, d.reader(ctx), query, args...)
+	if err != nil {
+		return err
+	}
+	defer release() // nolint: errcheck
+	defer rows.Close()
 
-		err = rows.Scan(&rule.PType, &rule.V0, &rule.V1, &rule.V2, &rule.V3, &rule.V4, &rule.V5)
+	for rows.Next() {
+		line, err := d.scanRule(rows)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		rules = append(rules, rule)
+		if err = fn(line); err != nil {
+			return err
+		}
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, err
+	return rows.Err()
+}
+
+// scanRule scan a row to rule by the table columns, extra are the destinations of the columns after the rule columns.
+// NULL values are scanned as empty strings, they may be written by other adapters sharing the table.
+func (d dao) scanRule(rows *sql.Rows, extra ...interface{}) (rule, error) {
+	values := make([]sql.NullString, len(d.columns))
+
+	dest := make([]interface{}, len(values), len(values)+len(extra))
+	for idx := range values {
+		dest[idx] = &values[idx]
 	}
 
-	return rules, nil
+	dest = append(dest, extra...)
+
+	if err := rows.Scan(dest...); err != nil {
+		return rule{}, err
+	}
+
+	line := rule{
+		PType:  values[0].String,
+		Values: make([]string, len(values)-1),
+	}
+
+	for idx := range line.Values {
+		line.Values[idx] = values[idx+1].String
+	}
+
+	return line, nil
 }
 
-// execSQL exec sql.
+// execSQL exec sql by the cached statement.
 func (d dao) execSQL(ctx context.Context, query string, args ...interface{}) error {
-	_, err := d.db.ExecContext(ctx, query, args...)
+	_, err := d.exec(ctx, d.db, query, args...)
+
+	return d.wrapError(err)
+}
+
+// execDDL exec the DDL statements, they are not prepared, e.g. SQLite executes multiple statements.
+func (d dao) execDDL(ctx context.Context, query string) error {
+	_, err := d.db.ExecContext(ctx, query)
+
+	return err
+}
+
+// exec exec the query by executor, the query is executed by the cached statement if the statement can be cached.
+func (d dao) exec(ctx context.Context, executor DBTX, query string, args ...interface{}) (sql.Result, error) {
+	stmt, release, ok, err := d.stmts.stmt(ctx, executor, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return executor.ExecContext(ctx, query, args...)
+	}
+
+	defer release() // nolint: errcheck
+
+	return stmt.ExecContext(ctx, args...)
+}
+
+// query query by executor, the release func is called after the rows are closed.
+func (d dao) query(ctx context.Context, executor DBTX, query string, args ...interface{}) (*sql.Rows, func() error, error) {
+	stmt, release, ok, err := d.stmts.stmt(ctx, executor, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !ok {
+		rows, err := executor.QueryContext(ctx, query, args...)
+
+		return rows, func() error { return nil }, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		_ = release()
+
+		return nil, nil, err
+	}
+
+	return rows, release, nil
+}
+
+// prepare returns the statement of query for executor, and the func to close it after use.
+// The statement is cached if it can be cached, or it is prepared and closed after use.
+func (d dao) prepare(ctx context.Context, executor DBTX, query string) (*sql.Stmt, func() error, error) {
+	stmt, release, ok, err := d.stmts.stmt(ctx, executor, query)
+	if err != nil || ok {
+		return stmt, release, err
+	}
+
+	stmt, err = executor.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stmt, stmt.Close, nil
+}
+
+// wrapError wrap the unique constraint violation to *DuplicateRuleError.
+func (d dao) wrapError(err error) error {
+	if err != nil && d.dialect.IsDuplicateError(err) {
+		return &DuplicateRuleError{Err: err}
+	}
 
 	return err
 }
 
+// txBeginner  the executors which can begin a transaction, e.g. *sql.DB and *sql.Conn.
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// daoTx  the transaction of the dao, tx is nil if the executor is a transaction of the caller.
+type daoTx struct {
+	DBTX
+
+	tx *sql.Tx
+
+	// beginner  began tx, the cached statements of the db are used in tx by StmtContext.
+	beginner txBeginner
+}
+
+// Commit commit the transaction, the transaction of the caller is committed by the caller.
+func (t daoTx) Commit() error {
+	if t.tx == nil {
+		return nil
+	}
+
+	return t.tx.Commit()
+}
+
+// Rollback rollback the transaction, the transaction of the caller is rolled back by the caller.
+func (t daoTx) Rollback() error {
+	if t.tx == nil {
+		return nil
+	}
+
+	return t.tx.Rollback()
+}
+
+// beginTx begin a transaction by the executor, or use the executor if it is a transaction already.
+func (d dao) beginTx(ctx context.Context) (daoTx, error) {
+	beginner, ok := d.db.(txBeginner)
+	if !ok {
+		return daoTx{DBTX: d.db}, nil
+	}
+
+	tx, err := beginner.BeginTx(ctx, nil)
+	if err != nil {
+		return daoTx{}, err
+	}
+
+	return daoTx{DBTX: tx, tx: tx, beginner: beginner}, nil
+}
+
 type txData struct {
 	step  string
 	query string
 	args  []interface{}
+
+	// check  optional, it is called in the transaction after the query, e.g. to verify the written rows.
+	check func(ctx context.Context, tx DBTX) error
 }
 
 // execTxSQL exec transaction sql rows.
 func (d dao) execTxSQL(ctx context.Context, beforeTxData, afterTxData txData, query string, args [][]interface{}) error {
-	tx, err := d.db.BeginTx(ctx, nil)
+	tx, err := d.beginTx(ctx)
 	if err != nil {
 		return fmt.Errorf("begin tx err: %w", err)
 	}
 
 	var (
-		step string
-		stmt *sql.Stmt
+		step      string
+		stmt      *sql.Stmt
+		closeStmt func() error
 	)
 
 	if beforeTxData.query != "" {
-		if _, err = tx.ExecContext(ctx, beforeTxData.query, beforeTxData.args...); err != nil {
+		if _, err = d.exec(ctx, tx, beforeTxData.query, beforeTxData.args...); err != nil {
 			step = beforeTxData.step + " before prepare"
 			goto ROLLBACK
 		}
 	}
 
-	if stmt, err = tx.PrepareContext(ctx, query); err != nil {
+	if query == d.sqlCopyFrom {
+		// the COPY statement belongs to the transaction, it is not cached.
+		stmt, err = tx.PrepareContext(ctx, query)
+		closeStmt = func() error { return stmt.Close() }
+	} else {
+		stmt, closeStmt, err = d.prepare(ctx, tx, query)
+	}
+
+	if err != nil {
 		step = "prepare context"
 		goto ROLLBACK
 	}
 
 	for _, arg := range args {
 		if _, err = stmt.ExecContext(ctx, arg...); err != nil {
+			_ = closeStmt()
 			step = "stmt exec context"
 			goto ROLLBACK
 		}
 	}
 
-	if err = stmt.Close(); err != nil {
+	if err = closeStmt(); err != nil {
 		step = "stmt close"
 		goto ROLLBACK
 	}
 
 	if afterTxData.query != "" {
-		if _, err = tx.ExecContext(ctx, afterTxData.query, afterTxData.args...); err != nil {
+		if _, err = d.exec(ctx, tx, afterTxData.query, afterTxData.args...); err != nil {
 			step = afterTxData.step + " after stmt"
 			goto ROLLBACK
 		}
 	}
 
+	if afterTxData.check != nil {
+		if err = afterTxData.check(ctx, tx); err != nil {
+			step = "check rows"
+			goto ROLLBACK
+		}
+	}
+
 	if err = tx.Commit(); err != nil {
 		step = "commit"
 		goto ROLLBACK
@@ -209,46 +789,87 @@ func (d dao) execTxSQL(ctx context.Context, beforeTxData, afterTxData txData, qu
 ROLLBACK:
 
 	if err1 := tx.Rollback(); err1 != nil {
-		return fmt.Errorf("%s err: %w, rollback err: %w", step, err, err1)
+		return d.wrapError(fmt.Errorf("%s err: %w, rollback err: %w", step, err, err1))
 	}
 
-	return fmt.Errorf("%s err: %w", step, err)
+	return d.wrapError(fmt.Errorf("%s err: %w", step, err))
 }
 
 // CreateTable create a table.
 func (d dao) CreateTable(ctx context.Context) error {
-	return d.execSQL(ctx, d.sqlCreateTable)
+	return d.execDDL(ctx, d.sqlCreateTable)
 }
 
-// IsTableExist check the table exists.
-func (d dao) IsTableExist(ctx context.Context) bool {
-	return d.execSQL(ctx, d.sqlTableExist) == nil
+// IsTableExist check the table exists by the database catalog.
+// The errors are returned to the caller, they do not mean the table is missing.
+func (d dao) IsTableExist(ctx context.Context) (bool, error) {
+	return d.isTableExist(ctx, d.tableExistArgs)
 }
 
-// SelectAll select all data from the table.
-func (d dao) SelectAll(ctx context.Context) ([]rule, error) {
-	return d.querySQL(ctx, d.sqlSelectAll)
-}
+// existingDao check the table exists, and returns the dao of the existing table.
+// If the database folds the unquoted identifiers to lower case, and the table of the mixed case names does not exist,
+// the table of the lower case names is used, e.g. "CasbinRule" was "casbinrule" in PostgreSQL before quoting.
+func (d dao) existingDao(ctx context.Context, o options) (dao, bool, error) {
+	exist, err := d.IsTableExist(ctx)
+	if err != nil || exist || !d.templates.LowerUnquoted {
+		return d, exist, err
+	}
 
-// SelectRows select eligible data by args from the table.
-func (d dao) SelectRows(ctx context.Context, query string, args ...interface{}) ([]rule, error) {
-	if len(args) == 0 {
-		return d.querySQL(ctx, query)
+	tableName := strings.ToLower(d.tableName)
+	o.schema = strings.ToLower(d.schema)
+
+	if tableName == d.tableName && o.schema == d.schema {
+		return d, false, nil
 	}
 
-	query = d.rebindSQL(query)
+	mapping := make(map[string]string, len(o.columnMapping))
+	for key, name := range o.columnMapping {
+		mapping[key] = strings.ToLower(name)
+	}
 
-	return d.querySQL(ctx, query, args...)
+	o.columnMapping = mapping
+
+	folded := newDao(d.db, d.dialect, tableName, o)
+	folded.copyFrom = d.copyFrom
+
+	if exist, err = folded.IsTableExist(ctx); err != nil || !exist {
+		return d, false, err
+	}
+
+	return folded, true, nil
+}
+
+// IsMigrationTableExist check the version table exists by the database catalog.
+func (d dao) IsMigrationTableExist(ctx context.Context) (bool, error) {
+	return d.isTableExist(ctx, d.migrationTableExistArgs)
 }
 
-// SelectByCondition .
+// isTableExist check the table of args exists by sqlTableExist.
+func (d dao) isTableExist(ctx context.Context, args []interface{}) (bool, error) {
+	var exist int
+
+	err := d.db.QueryRowContext(ctx, d.sqlTableExist, args...).Scan(&exist)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("check table exists err: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+// SelectByCondition select the rules before updating them, so they are read from the primary db.
 func (d dao) SelectByCondition(ctx context.Context, whereCondition string, args ...interface{}) ([]rule, error) {
+	ctx = ReadFromPrimary(ctx)
+
 	var buf bytes.Buffer
 
 	buf.Grow(128)
 	buf.WriteString(d.sqlSelectWhere)
 	// this is for reuse the SQL
-	buf.WriteString("p_type=?")
+	buf.WriteString(d.columns[0])
+	buf.WriteString("=?")
 	buf.WriteString(whereCondition)
 
 	query := d.rebindSQL(buf.String())
@@ -256,58 +877,331 @@ func (d dao) SelectByCondition(ctx context.Context, whereCondition string, args
 	return d.querySQL(ctx, query, args...)
 }
 
-// SelectByFilter select eligible data by Filter from the table.
-func (d dao) SelectByFilter(ctx context.Context, filterData [maxParameterCount]filterData) (lines []rule, err error) {
-	var (
-		sqlBuf bytes.Buffer
-		buf    bytes.Buffer
-	)
+// SelectByFilter select eligible data by the filters from the table, and call fn with each row.
+// Each filter is ordered by the table columns, starts with p_type, the rows matched by any filter are selected.
+func (d dao) SelectByFilter(ctx context.Context, filters [][]Condition, fn func(line rule) error) error {
+	condition, args, err := d.genFilterCondition(filters)
+	if err != nil {
+		return err
+	}
 
-	sqlBuf.Grow(64)
-	sqlBuf.WriteString(d.sqlSelectWhere)
+	if d.pageSize > 0 {
+		return d.selectPages(ctx, condition, args, fn)
+	}
 
-	args := make([]string, 0, maxParameterCount)
+	if condition == "" {
+		return d.queryEach(ctx, d.sqlSelectAll, nil, fn)
+	}
 
-	for _, col := range filterData {
-		l := len(col.arg)
-		if l == 0 {
-			continue
-		}
+	return d.queryEach(ctx, d.rebindSQL(d.sqlSelectWhere+condition), args, fn)
+}
 
-		switch sqlBuf.Bytes()[sqlBuf.Len()-1] {
-		case '?', ')':
-			sqlBuf.WriteString(" AND ")
+// selectPages select the rows by the condition in pages ordered by the id column, and call fn with each row.
+// Each page starts after the last id of the previous page, the last page has fewer rows than the page size.
+func (d dao) selectPages(ctx context.Context, condition string, args []interface{}, fn func(line rule) error) error {
+	firstQuery := d.sqlSelectPage
+	nextCondition := d.idColumn + ">?"
+
+	if condition != "" {
+		firstQuery = d.sqlSelectPageWhere + condition
+		nextCondition = condition + " AND " + nextCondition
+	}
+
+	firstQuery = d.rebindSQL(firstQuery + d.sqlPageOrder)
+	nextQuery := d.rebindSQL(d.sqlSelectPageWhere + nextCondition + d.sqlPageOrder)
+
+	reader := d.reader(ctx)
+
+	if beginner, ok := reader.(txBeginner); ok && d.snapshot {
+		tx, err := beginner.BeginTx(ctx, &sql.TxOptions{Isolation: d.templates.SnapshotIsolation})
+		if err != nil {
+			return fmt.Errorf("begin snapshot tx err: %w", err)
 		}
 
-		sqlBuf.WriteString(col.fieldName)
+		// the snapshot only reads the rows, so it is rolled back.
+		defer tx.Rollback() // nolint: errcheck
 
-		if l == 1 {
-			sqlBuf.WriteString("=?")
-			args = append(args, col.arg[0])
-		} else {
-			buf.Grow(l * 2)
-			for i := 0; i < l; i++ {
-				buf.WriteString("?,")
+		reader = daoTx{DBTX: tx, tx: tx, beginner: beginner}
+	}
+
+	count, lastID, err := d.selectPage(ctx, reader, firstQuery, args, fn)
+
+	for err == nil && count == d.pageSize {
+		pageArgs := make([]interface{}, 0, len(args)+1)
+		pageArgs = append(pageArgs, args...)
+		pageArgs = append(pageArgs, lastID)
+
+		count, lastID, err = d.selectPage(ctx, reader, nextQuery, pageArgs, fn)
+	}
+
+	return err
+}
+
+// selectPage select a page by reader, and call fn with each row.
+// It returns the number of the rows and the last id.
+func (d dao) selectPage(ctx context.Context, reader DBTX, query string, args []interface{}, fn func(line rule) error) (count int, lastID int64, err error) {
+	rows, release, err := d.query(ctx, reader, query, args...)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer release() // nolint: errcheck
+	defer rows.Close()
+
+	for rows.Next() {
+		line, err := d.scanRule(rows, &lastID)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		count++
+
+		if err = fn(line); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return count, lastID, rows.Err()
+}
+
+// SelectAudited select the rules with the audit timestamps by the filters from the table.
+func (d dao) SelectAudited(ctx context.Context, filters [][]Condition) ([]AuditedRule, error) {
+	condition, args, err := d.genFilterCondition(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	query := d.sqlSelectAudited
+	if condition != "" {
+		query = d.rebindSQL(d.sqlSelectAuditedWhere + condition)
+	}
+
+	rows, release, err := d.query(ctx, d.reader(ctx), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer release() // nolint: errcheck
+	defer rows.Close()
+
+	result := make([]AuditedRule, 0, 128)
+
+	for rows.Next() {
+		var createdAt, updatedAt nullTime
+
+		line, err := d.scanRule(rows, &createdAt, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		data := line.Data()
+		if len(data) == 0 {
+			continue
+		}
+
+		result = append(result, AuditedRule{
+			PType:     data[0],
+			Rule:      data[1:],
+			CreatedAt: createdAt.Time,
+			UpdatedAt: updatedAt.Time,
+		})
+	}
+
+	return result, rows.Err()
+}
+
+// genFilterCondition generate the where condition without the "WHERE" keyword and the args of the filters,
+// the conditions of the filters are joined by OR, so each matched row is selected once.
+// The condition is empty if there is no filter or a filter has no values.
+func (d dao) genFilterCondition(filters [][]Condition) (string, []interface{}, error) {
+	if len(filters) == 1 {
+		return d.genConditions(filters[0])
+	}
+
+	var args []interface{}
+
+	conditions := make([]string, 0, len(filters))
+	seen := make(map[string]struct{}, len(filters))
+
+	for _, filterData := range filters {
+		condition, filterArgs, err := d.genConditions(filterData)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if condition == "" {
+			return "", nil, nil
+		}
+
+		// the same filters are only queried once.
+		key := condition + "\x00" + rowKey(filterArgs)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+
+		conditions = append(conditions, "("+condition+")")
+		args = append(args, filterArgs...)
+	}
+
+	switch len(conditions) {
+	case 0:
+		return "", nil, nil
+	case 1:
+		return conditions[0], args, nil
+	default:
+		return "(" + strings.Join(conditions, " OR ") + ")", args, nil
+	}
+}
+
+// genConditions generate the where condition without the "WHERE" keyword and the args of a filter.
+// filterData is ordered by the table columns, starts with p_type.
+// The condition is empty if the filter has no values.
+func (d dao) genConditions(filterData []Condition) (string, []interface{}, error) {
+	var sqlBuf bytes.Buffer
+
+	sqlBuf.Grow(64)
+
+	args := make([]interface{}, 0, len(d.columns))
+
+	for idx, condition := range filterData {
+		arg := condition.Values
+
+		l := len(arg)
+		if l == 0 {
+			continue
+		}
+
+		if idx >= len(d.columns) {
+			return "", nil, fmt.Errorf("filter column index %d out of range, the table only has %d value columns", idx-1, len(d.columns)-1)
+		}
+
+		// the condition of the empty value has no args.
+		if sqlBuf.Len() != 0 {
+			sqlBuf.WriteString(" AND ")
+		}
+
+		if condition.Op == OpEqual || condition.Op == OpNotEqual {
+			equal, values := d.genEqualCondition(d.columns[idx], condition)
+
+			sqlBuf.WriteString(equal)
+
+			for _, value := range values {
+				args = append(args, value)
+			}
+
+			continue
+		}
+
+		patterns, err := d.genPatternCondition(d.columns[idx], condition)
+		if err != nil {
+			return "", nil, err
+		}
+
+		sqlBuf.WriteString(patterns)
+
+		for _, value := range arg {
+			args = append(args, d.likePattern(condition.Op, value))
+		}
+	}
+
+	return sqlBuf.String(), args, nil
+}
+
+// genEqualCondition returns the condition of OpEqual or OpNotEqual for the column, and the values of its placeholders.
+// If the database stores the empty strings as NULL, the empty value is matched by IS NULL, see Templates.EmptyIsNull.
+func (d dao) genEqualCondition(column string, condition Condition) (string, []string) {
+	not := condition.Op == OpNotEqual
+	values := condition.Values
+
+	var hasEmpty bool
+
+	if d.templates.EmptyIsNull {
+		values = make([]string, 0, len(condition.Values))
+
+		for _, value := range condition.Values {
+			if value == "" {
+				hasEmpty = true
+				continue
 			}
 
-			buf.Truncate(buf.Len() - 1)
+			values = append(values, value)
+		}
+	}
+
+	switch {
+	case !hasEmpty && not:
+		return fmt.Sprintf(d.templates.NotMatch, column, genInCondition(len(values), true)), values
+	case !hasEmpty:
+		return column + genInCondition(len(values), false), values
+	case len(values) == 0 && not:
+		return column + " IS NOT NULL", nil
+	case len(values) == 0:
+		return column + " IS NULL", nil
+	case not:
+		return column + " IS NOT NULL AND " + column + genInCondition(len(values), true), values
+	default:
+		return "(" + column + " IS NULL OR " + column + genInCondition(len(values), false) + ")", values
+	}
+}
+
+// genInCondition returns "=?" or " IN (?,...)" for count values, or the negative condition if not is true.
+func genInCondition(count int, not bool) string {
+	switch {
+	case count == 1 && not:
+		return "<>?"
+	case count == 1:
+		return "=?"
+	case not:
+		return " NOT IN (" + genPlaceholders(count) + ")"
+	default:
+		return " IN (" + genPlaceholders(count) + ")"
+	}
+}
 
-			sqlBuf.WriteString(" IN (")
-			sqlBuf.Write(buf.Bytes())
-			sqlBuf.WriteByte(')')
+// genPatternCondition generate the Like conditions of the column, they are joined by OR.
+func (d dao) genPatternCondition(column string, condition Condition) (string, error) {
+	switch condition.Op {
+	case OpPrefix, OpLike, OpGlob:
+	default:
+		return "", fmt.Errorf("invalid filter operator: %d", condition.Op)
+	}
 
-			args = append(args, col.arg...)
+	like := fmt.Sprintf(d.templates.Like, column)
+	if len(condition.Values) == 1 {
+		return like, nil
+	}
 
-			buf.Reset()
+	return "(" + strings.Repeat(like+" OR ", len(condition.Values)-1) + like + ")", nil
+}
+
+// likePattern returns the Like pattern of the value by op, the literal characters are escaped by likeEscape.
+func (d dao) likePattern(op Operator, value string) string {
+	var buf strings.Builder
+
+	buf.Grow(len(value) + 8)
+
+	for _, r := range value {
+		switch {
+		case op != OpLike && (r == '%' || r == '_' || r == likeEscape):
+			buf.WriteRune(likeEscape)
+			buf.WriteRune(r)
+		case op == OpGlob && r == '*':
+			buf.WriteByte('%')
+		case op == OpGlob && r == '?':
+			buf.WriteByte('_')
+		case strings.ContainsRune(d.templates.LikeEscapes, r):
+			buf.WriteRune(likeEscape)
+			buf.WriteRune(r)
+		default:
+			buf.WriteRune(r)
 		}
 	}
 
-	params := make([]interface{}, len(args))
-	for idx := range args {
-		params[idx] = args[idx]
+	if op == OpPrefix {
+		buf.WriteByte('%')
 	}
 
-	return d.SelectRows(ctx, sqlBuf.String(), params...)
+	return buf.String()
 }
 
 // InsertRow insert one row to the table.
@@ -317,7 +1211,85 @@ func (d dao) InsertRow(ctx context.Context, args ...interface{}) error {
 
 // InsertRows insert multiple rows to the table by transaction.
 func (d dao) InsertRows(ctx context.Context, args [][]interface{}) error {
-	return d.execTxSQL(ctx, txData{}, txData{}, d.sqlInsertRow, args)
+	query, batches, last := d.genInsertBatches(args)
+
+	return d.execTxSQL(ctx, txData{}, last, query, batches)
+}
+
+// batchRows returns the number of rows in a multi-row insert statement, the rows have columnCount args.
+func (d dao) batchRows(columnCount int) int {
+	if d.sqlInsertBatch == "" {
+		return 1
+	}
+
+	rows := d.batchSize
+	if limit := d.templates.MaxInsertRows; limit > 0 && rows > limit {
+		rows = limit
+	}
+
+	if limit := d.templates.MaxParams; limit > 0 && columnCount > 0 && rows > limit/columnCount {
+		rows = limit / columnCount
+	}
+
+	if rows < 1 {
+		return 1
+	}
+
+	return rows
+}
+
+// genInsertSQL generate the insert SQL of count rows.
+func (d dao) genInsertSQL(count int) string {
+	if count == 1 || d.sqlInsertBatch == "" {
+		return d.sqlInsertRow
+	}
+
+	// the templates wrap the values in parentheses.
+	values := strings.Repeat("("+d.insertValues+"),", count)
+	values = values[1 : len(values)-2]
+
+	return d.rebindSQL(fmt.Sprintf(d.sqlInsertBatch, values))
+}
+
+// genInsertBatches split the rows into the multi-row insert statements,
+// the full batches share the prepared query, and the rest rows are inserted by the last statement.
+func (d dao) genInsertBatches(rows [][]interface{}) (string, [][]interface{}, txData) {
+	if len(rows) == 0 {
+		return d.sqlInsertRow, rows, txData{}
+	}
+
+	size := d.batchRows(len(rows[0]))
+	if size == 1 {
+		return d.sqlInsertRow, rows, txData{}
+	}
+
+	batches := make([][]interface{}, 0, len(rows)/size+1)
+	for start := 0; start < len(rows); start += size {
+		end := start + size
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		args := make([]interface{}, 0, (end-start)*len(rows[0]))
+		for _, row := range rows[start:end] {
+			args = append(args, row...)
+		}
+
+		batches = append(batches, args)
+	}
+
+	if len(rows) <= size {
+		return d.genInsertSQL(len(rows)), batches, txData{}
+	}
+
+	last := txData{step: "insert rows"}
+	if rest := len(rows) % size; rest != 0 {
+		last.query = d.genInsertSQL(rest)
+		last.args = batches[len(batches)-1]
+		batches = batches[:len(batches)-1]
+	}
+
+	return d.genInsertSQL(size), batches, last
 }
 
 // UpdateRow update one row to the table.
@@ -350,23 +1322,260 @@ func (d dao) DeleteRows(ctx context.Context, args [][]interface{}) error {
 
 // DeleteAllAndInsertRows clear table and insert new rows.
 func (d dao) DeleteAllAndInsertRows(ctx context.Context, rules [][]interface{}) error {
-	return d.execTxSQL(ctx, txData{step: "delete all", query: d.sqlDeleteAll}, txData{}, d.sqlInsertRow, rules)
+	return d.deleteAndInsertRows(ctx, txData{step: "delete all", query: d.sqlDeleteAll}, rules, nil)
+}
+
+// ReplaceFilteredRows delete the rows matched by the filters and insert new rows in one transaction,
+// the rows out of the filters are kept. All the rows are replaced if a filter has no values.
+func (d dao) ReplaceFilteredRows(ctx context.Context, filters [][]Condition, rules [][]interface{}) error {
+	condition, args, err := d.genFilterCondition(filters)
+	if err != nil {
+		return err
+	}
+
+	if condition == "" {
+		return d.DeleteAllAndInsertRows(ctx, rules)
+	}
+
+	deleteQuery := d.rebindSQL(d.sqlDeleteWhere + condition)
+	selectQuery := d.rebindSQL(d.sqlSelectWhere + condition)
+
+	check := func(ctx context.Context, tx DBTX) error {
+		return d.checkFilteredRows(ctx, tx, selectQuery, args, rules)
+	}
+
+	return d.deleteAndInsertRows(ctx, txData{step: "delete filtered rows", query: deleteQuery, args: args}, rules, check)
+}
+
+// deleteAndInsertRows execute the delete statement and insert new rows in one transaction,
+// check is called before the commit if it is not nil.
+func (d dao) deleteAndInsertRows(ctx context.Context, deleteData txData, rules [][]interface{},
+	check func(ctx context.Context, tx DBTX) error) error {
+	if d.copyFrom == copyFromStmt && len(rules) != 0 {
+		// the statement without args flushes the rows.
+		args := make([][]interface{}, 0, len(rules)+1)
+		args = append(args, rules...)
+		args = append(args, nil)
+
+		return d.execTxSQL(ctx, deleteData, txData{check: check}, d.sqlCopyFrom, args)
+	}
+
+	// the connection of pgx is only reached by the pool, the transactions of the caller insert the rows.
+	if db, ok := d.db.(*sql.DB); ok && d.copyFrom == copyFromPgConn && len(rules) != 0 {
+		return d.copyPgConnTx(ctx, db, deleteData, rules, check)
+	}
+
+	query, batches, last := d.genInsertBatches(rules)
+	last.check = check
+
+	return d.execTxSQL(ctx, deleteData, last, query, batches)
+}
+
+// checkFilteredRows returns ErrRuleOutOfFilter if a rule is not selected by the filter query in tx after the save,
+// the rule would be inserted again by every save, because the rows out of the filters are kept.
+// The rules are matched by the database, so the collation of the filter is the same as the load.
+func (d dao) checkFilteredRows(ctx context.Context, tx DBTX, query string, args []interface{}, rules [][]interface{}) error {
+	missing, _, err := d.diffRows(ctx, tx, query, args, rules)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) != 0 {
+		return fmt.Errorf("%w: %v", ErrRuleOutOfFilter, missing[0])
+	}
+
+	return nil
+}
+
+// DiffRows replace the rows of the table matched by the filters by rules, only the difference is applied in one transaction.
+// All the rows are compared if there is no filter. It returns the number of the inserted rules and the deleted rules.
+func (d dao) DiffRows(ctx context.Context, filters [][]Condition, rules [][]interface{}) (added, removed int, err error) {
+	condition, args, err := d.genFilterCondition(filters)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	selectQuery := d.sqlSelectAll
+	if condition != "" {
+		selectQuery = d.rebindSQL(d.sqlSelectWhere + condition)
+	}
+
+	tx, err := d.beginTx(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("begin tx err: %w", err)
+	}
+
+	var (
+		step                   string
+		insertRows, deleteRows [][]interface{}
+		query                  string
+		batches                [][]interface{}
+		last                   txData
+	)
+
+	if insertRows, deleteRows, err = d.diffRows(ctx, tx, selectQuery, args, rules); err != nil {
+		step = "select rows"
+		goto ROLLBACK
+	}
+
+	if err = d.execRows(ctx, tx, d.sqlDeleteRow, deleteRows); err != nil {
+		step = "delete rows"
+		goto ROLLBACK
+	}
+
+	query, batches, last = d.genInsertBatches(insertRows)
+	if err = d.execRows(ctx, tx, query, batches); err != nil {
+		step = "insert rows"
+		goto ROLLBACK
+	}
+
+	if last.query != "" {
+		if _, err = d.exec(ctx, tx, last.query, last.args...); err != nil {
+			step = last.step
+			goto ROLLBACK
+		}
+	}
+
+	// the rules found by the select are in the filters.
+	if condition != "" && len(insertRows) != 0 {
+		if err = d.checkFilteredRows(ctx, tx, selectQuery, args, rules); err != nil {
+			step = "check rows"
+			goto ROLLBACK
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		step = "commit"
+		goto ROLLBACK
+	}
+
+	return len(insertRows), len(deleteRows), nil
+
+ROLLBACK:
+
+	if err1 := tx.Rollback(); err1 != nil {
+		return 0, 0, d.wrapError(fmt.Errorf("%s err: %w, rollback err: %w", step, err, err1))
+	}
+
+	return 0, 0, d.wrapError(fmt.Errorf("%s err: %w", step, err))
+}
+
+// diffRows select the rows of the table by tx and query, and compare them with rules.
+// It returns the rules which are not in the rows, and the rows which are not in rules, they are deduplicated.
+func (d dao) diffRows(ctx context.Context, tx DBTX, query string, queryArgs []interface{}, rules [][]interface{}) (insertRows, deleteRows [][]interface{}, err error) {
+	rows, release, err := d.query(ctx, tx, query, queryArgs...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release() // nolint: errcheck
+	defer rows.Close()
+
+	existing := make(map[string]struct{}, len(rules))
+
+	for rows.Next() {
+		line, err := d.scanRule(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		args := make([]interface{}, 0, len(d.columns))
+		args = append(args, line.PType)
+
+		for _, value := range line.Values {
+			args = append(args, value)
+		}
+
+		key := rowKey(args)
+		if _, ok := existing[key]; ok {
+			continue
+		}
+
+		existing[key] = struct{}{}
+		deleteRows = append(deleteRows, args)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	wanted := make(map[string]struct{}, len(rules))
+
+	for _, args := range rules {
+		key := rowKey(args)
+		if _, ok := wanted[key]; ok {
+			continue
+		}
+
+		wanted[key] = struct{}{}
+
+		if _, ok := existing[key]; !ok {
+			insertRows = append(insertRows, args)
+		}
+	}
+
+	result := deleteRows[:0]
+
+	for _, args := range deleteRows {
+		if _, ok := wanted[rowKey(args)]; !ok {
+			result = append(result, args)
+		}
+	}
+
+	return insertRows, result, nil
+}
+
+// rowKey returns the key of the row args to compare the rules.
+func rowKey(args []interface{}) string {
+	var buf strings.Builder
+
+	for _, arg := range args {
+		fmt.Fprint(&buf, arg)
+		buf.WriteByte(0)
+	}
+
+	return buf.String()
+}
+
+// execRows prepare the query by tx, and execute it with each args.
+func (d dao) execRows(ctx context.Context, tx DBTX, query string, args [][]interface{}) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	stmt, closeStmt, err := d.prepare(ctx, tx, query)
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		if _, err = stmt.ExecContext(ctx, arg...); err != nil {
+			_ = closeStmt()
+
+			return err
+		}
+	}
+
+	return closeStmt()
 }
 
 // DeleteByArgs delete eligible data.
 func (d dao) DeleteByArgs(ctx context.Context, ptype string, rule []string) error {
 	var sqlBuf bytes.Buffer
 
+	if len(rule) >= len(d.columns) {
+		return errRuleTooLong(len(rule), len(d.columns)-1)
+	}
+
 	sqlBuf.Grow(128)
 	sqlBuf.WriteString(d.sqlDeleteByArgs)
 
-	args := make([]interface{}, 0, maxParameterCount)
+	args := make([]interface{}, 0, len(d.columns))
 	args = append(args, ptype)
 
 	for idx, arg := range rule {
 		if arg != "" {
-			sqlBuf.WriteString(" AND v")
-			sqlBuf.WriteString(strconv.Itoa(idx))
+			sqlBuf.WriteString(" AND ")
+			sqlBuf.WriteString(d.columns[idx+1])
 			sqlBuf.WriteString("=?")
 
 			args = append(args, arg)
@@ -386,26 +1595,36 @@ func (d dao) DeleteByCondition(ctx context.Context, condition string, args ...in
 	return d.execSQL(ctx, deleteQuery, args...)
 }
 
+// PurgeDeleted delete the soft deleted rows which are deleted earlier than seconds ago.
+func (d dao) PurgeDeleted(ctx context.Context, seconds int64) (int64, error) {
+	result, err := d.exec(ctx, d.db, d.sqlPurgeDeleted, seconds)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
 // GenFilteredCondition .
 func (d dao) GenFilteredCondition(ptype string, fieldIndex int, fieldValues ...string) (string, []interface{}) {
 	var whereConditionBuf bytes.Buffer
 
 	whereConditionBuf.Grow(64)
 
-	args := make([]interface{}, 0, maxParameterCount)
+	args := make([]interface{}, 0, len(d.columns))
 	args = append(args, ptype)
 
 	var value string
 
 	l := fieldIndex + len(fieldValues)
 
-	for idx := 0; idx < maxParameterCount-1; idx++ {
+	for idx := 0; idx < len(d.columns)-1; idx++ {
 		if fieldIndex <= idx && idx < l {
 			value = fieldValues[idx-fieldIndex]
 
 			if value != "" {
-				whereConditionBuf.WriteString(" AND v")
-				whereConditionBuf.WriteString(strconv.Itoa(idx))
+				whereConditionBuf.WriteString(" AND ")
+				whereConditionBuf.WriteString(d.columns[idx+1])
 				whereConditionBuf.WriteString("=?")
 
 				args = append(args, value)
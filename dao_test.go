@@ -0,0 +1,569 @@
+// Copyright 2026 by Blank-Xu. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqladapter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// nolint: funlen,paralleltest
+func TestNewDao(t *testing.T) {
+	tests := []struct {
+		name       string
+		driverName string
+		opts       []Option
+		got        func(d dao) string
+		want       string
+	}{
+		{
+			name:       "01 default insert",
+			driverName: "mysql",
+			got:        func(d dao) string { return d.sqlInsertRow },
+			want:       "INSERT INTO `casbin_rule` (`p_type`,`v0`,`v1`,`v2`,`v3`,`v4`,`v5`) VALUES (?,?,?,?,?,?,?)",
+		},
+		{
+			name:       "02 column count insert",
+			driverName: "postgres",
+			opts:       []Option{WithColumnCount(3)},
+			got:        func(d dao) string { return d.sqlInsertRow },
+			want:       `INSERT INTO "casbin_rule" ("p_type","v0","v1","v2") VALUES ($1,$2,$3,$4)`,
+		},
+		{
+			name:       "03 column count update",
+			driverName: "sqlserver",
+			opts:       []Option{WithColumnCount(2)},
+			got:        func(d dao) string { return d.sqlUpdateRow },
+			want:       "UPDATE [casbin_rule] SET [p_type]=@p1,[v0]=@p2,[v1]=@p3 WHERE [p_type]=@p4 AND [v0]=@p5 AND [v1]=@p6",
+		},
+		{
+			name:       "04 column count select",
+			driverName: "sqlite3",
+			opts:       []Option{WithColumnCount(10)},
+			got:        func(d dao) string { return d.sqlSelectAll },
+			want:       `SELECT "p_type","v0","v1","v2","v3","v4","v5","v6","v7","v8","v9" FROM "casbin_rule"`,
+		},
+		{
+			name:       "05 ignore duplicates insert",
+			driverName: "postgres",
+			opts:       []Option{WithColumnCount(2), WithIgnoreDuplicates()},
+			got:        func(d dao) string { return d.sqlInsertRow },
+			want:       `INSERT INTO "casbin_rule" ("p_type","v0","v1") VALUES ($1,$2,$3) ON CONFLICT DO NOTHING`,
+		},
+		{
+			name:       "06 ignore duplicates insert",
+			driverName: "sqlserver",
+			opts:       []Option{WithColumnCount(1), WithIgnoreDuplicates()},
+			got:        func(d dao) string { return d.sqlInsertRow },
+			want: "INSERT INTO [casbin_rule] ([p_type],[v0]) SELECT @p1,@p2 WHERE NOT EXISTS " +
+				"(SELECT 1 FROM [casbin_rule] WITH (UPDLOCK, HOLDLOCK) WHERE [p_type]=@p1 AND [v0]=@p2)",
+		},
+		{
+			name:       "07 unique index",
+			driverName: "sqlite3",
+			opts:       []Option{WithColumnCount(2), WithUniqueIndex()},
+			got: func(d dao) string {
+				return d.sqlCreateTable[strings.LastIndex(d.sqlCreateTable, "\n")+1:]
+			},
+			want: `CREATE UNIQUE INDEX IF NOT EXISTS "uk_casbin_rule" ON "casbin_rule" ("p_type","v0","v1");`,
+		},
+		{
+			name:       "08 column mapping",
+			driverName: "mysql",
+			opts:       []Option{WithColumnCount(2), WithColumnMapping(map[string]string{"p_type": "ptype", "v1": "obj"})},
+			got:        func(d dao) string { return d.sqlDeleteRow },
+			want:       "DELETE FROM `casbin_rule` WHERE `ptype`=? AND `v0`=? AND `obj`=?",
+		},
+		{
+			name:       "09 schema",
+			driverName: "postgres",
+			opts:       []Option{WithColumnCount(1), WithSchema("Analytics")},
+			got:        func(d dao) string { return d.sqlSelectAll },
+			want:       `SELECT "p_type","v0" FROM "Analytics"."casbin_rule"`,
+		},
+		{
+			name:       "10 schema index",
+			driverName: "sqlite3",
+			opts:       []Option{WithColumnCount(1), WithSchema("main")},
+			got: func(d dao) string {
+				return d.sqlCreateTable[strings.LastIndex(d.sqlCreateTable, "\n")+1:]
+			},
+			want: `CREATE INDEX IF NOT EXISTS "main"."idx_casbin_rule" ON "casbin_rule" ("p_type","v0");`,
+		},
+		{
+			name:       "11 table exist",
+			driverName: "sqlserver",
+			got:        func(d dao) string { return fmt.Sprint(d.sqlTableExist, d.tableExistArgs, d.migrationTableExistArgs) },
+			want:       "SELECT 1 WHERE OBJECT_ID(@p1, 'U') IS NOT NULL[[casbin_rule]] [[casbin_rule_migrations]]",
+		},
+		{
+			name:       "12 schema table exist",
+			driverName: "postgres",
+			opts:       []Option{WithSchema("Analytics")},
+			got:        func(d dao) string { return d.sqlTableExist },
+			want:       "SELECT 1 FROM information_schema.tables WHERE table_schema=$1 AND table_name=$2",
+		},
+		{
+			name:       "13 timestamps insert",
+			driverName: "postgres",
+			opts:       []Option{WithColumnCount(1), WithTimestamps()},
+			got:        func(d dao) string { return d.sqlInsertRow },
+			want: `INSERT INTO "casbin_rule" ("p_type","v0","created_at","updated_at") ` +
+				`VALUES ($1,$2,CURRENT_TIMESTAMP,CURRENT_TIMESTAMP)`,
+		},
+		{
+			name:       "14 timestamps update",
+			driverName: "mysql",
+			opts:       []Option{WithColumnCount(1), WithTimestamps(), WithColumnMapping(map[string]string{"updated_at": "modified"})},
+			got:        func(d dao) string { return d.sqlUpdateRow },
+			want:       "UPDATE `casbin_rule` SET `p_type`=?,`v0`=?,`modified`=CURRENT_TIMESTAMP WHERE `p_type`=? AND `v0`=?",
+		},
+		{
+			name:       "15 timestamps column",
+			driverName: "sqlserver",
+			opts:       []Option{WithColumnCount(1), WithTimestamps()},
+			got:        func(d dao) string { return d.timestampsSteps(nil)[0] },
+			want:       "ALTER TABLE [casbin_rule] ADD [created_at] DATETIME2 DEFAULT CURRENT_TIMESTAMP NOT NULL",
+		},
+		{
+			name:       "16 soft delete row",
+			driverName: "postgres",
+			opts:       []Option{WithColumnCount(1), WithSoftDelete()},
+			got:        func(d dao) string { return d.sqlDeleteRow },
+			want:       `UPDATE "casbin_rule" SET "deleted_at"=CURRENT_TIMESTAMP WHERE "p_type"=$1 AND "v0"=$2 AND "deleted_at" IS NULL`,
+		},
+		{
+			name:       "17 soft delete select",
+			driverName: "sqlite3",
+			opts:       []Option{WithColumnCount(1), WithSoftDelete()},
+			got:        func(d dao) string { return d.sqlSelectAll },
+			want:       `SELECT "p_type","v0" FROM "casbin_rule" WHERE "deleted_at" IS NULL`,
+		},
+		{
+			name:       "18 soft delete unique index",
+			driverName: "sqlserver",
+			opts:       []Option{WithColumnCount(1), WithSoftDelete(), WithUniqueIndex()},
+			got: func(d dao) string {
+				return d.sqlCreateTable[strings.LastIndex(d.sqlCreateTable, "\n")+1:]
+			},
+			want: "CREATE UNIQUE INDEX [uk_casbin_rule] ON [casbin_rule] (rule_key) WHERE [deleted_at] IS NULL;",
+		},
+		{
+			name:       "19 soft delete unique key",
+			driverName: "mysql",
+			opts:       []Option{WithColumnCount(1), WithSoftDelete(), WithUniqueIndex()},
+			got: func(d dao) string {
+				return d.softDeleteSteps(map[int]struct{}{uniqueIndexMigrationVersion: {}})[2]
+			},
+			want: "ALTER TABLE `casbin_rule`\n" +
+				"    ADD COLUMN rule_key BINARY(32) AS (IF(`deleted_at` IS NULL,UNHEX(SHA2(CONCAT_WS(CHAR(31),`p_type`,`v0`),256)),NULL)) STORED,\n" +
+				"    ADD UNIQUE KEY `uk_casbin_rule` (rule_key)",
+		},
+		{
+			name:       "20 oracle delete row",
+			driverName: "oracle",
+			opts:       []Option{WithColumnCount(1)},
+			got:        func(d dao) string { return d.sqlDeleteRow },
+			want:       `DELETE FROM "CASBIN_RULE" WHERE DECODE("P_TYPE",:1,1)=1 AND DECODE("V0",:2,1)=1`,
+		},
+		{
+			name:       "21 oracle create table",
+			driverName: "oracle",
+			opts:       []Option{WithColumnCount(1), WithUniqueIndex()},
+			got:        func(d dao) string { return d.sqlCreateTable },
+			want: "BEGIN\n" +
+				"    EXECUTE IMMEDIATE 'CREATE TABLE \"CASBIN_RULE\"(\n" +
+				"    \"ID\" NUMBER(19) GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,\n" +
+				"    \"P_TYPE\" VARCHAR2(32),\n" +
+				"    \"V0\" VARCHAR2(255)\n" +
+				")';\n" +
+				"    EXECUTE IMMEDIATE 'CREATE INDEX \"IDX_CASBIN_RULE\" ON \"CASBIN_RULE\" (\"P_TYPE\",\"V0\")';\n" +
+				"    EXECUTE IMMEDIATE 'CREATE UNIQUE INDEX \"UK_CASBIN_RULE\" ON \"CASBIN_RULE\" (\"P_TYPE\",\"V0\")';\n" +
+				"END;",
+		},
+		{
+			name:       "22 postgres copy from",
+			driverName: "postgres",
+			opts:       []Option{WithColumnCount(1), WithTimestamps()},
+			got:        func(d dao) string { return d.sqlCopyFrom },
+			want:       `COPY "casbin_rule" ("p_type","v0") FROM STDIN`,
+		},
+		{
+			name:       "23 sqlserver page",
+			driverName: "sqlserver",
+			opts:       []Option{WithColumnCount(1), WithPageSize(100), WithSoftDelete()},
+			got:        func(d dao) string { return d.sqlSelectPage + d.sqlPageOrder },
+			want:       "SELECT [p_type],[v0],[id] FROM [casbin_rule] WHERE [deleted_at] IS NULL ORDER BY [id] OFFSET 0 ROWS FETCH NEXT 100 ROWS ONLY",
+		},
+		{
+			name:       "24 sqlite page create table",
+			driverName: "sqlite3",
+			opts:       []Option{WithColumnCount(1), WithPageSize(100)},
+			got:        func(d dao) string { return d.sqlCreateTable },
+			want: "\nCREATE TABLE IF NOT EXISTS \"casbin_rule\"(\n" +
+				"    \"id\" INTEGER PRIMARY KEY AUTOINCREMENT,\n" +
+				"    \"p_type\" VARCHAR(32) DEFAULT '' NOT NULL,\n" +
+				"    \"v0\" VARCHAR(255) DEFAULT '' NOT NULL,\n" +
+				"    CHECK (TYPEOF(\"p_type\") = 'text' AND\n" +
+				"           LENGTH(\"p_type\") <= 32),\n" +
+				"    CHECK (TYPEOF(\"v0\") = 'text' AND\n" +
+				"           LENGTH(\"v0\") <= 255)\n" +
+				");\n" +
+				"CREATE INDEX IF NOT EXISTS \"idx_casbin_rule\" ON \"casbin_rule\" (\"p_type\",\"v0\");",
+		},
+		{
+			name:       "25 soft delete filtered rows",
+			driverName: "mysql",
+			opts:       []Option{WithColumnCount(1), WithSoftDelete()},
+			got:        func(d dao) string { return d.sqlDeleteWhere },
+			want:       "UPDATE `casbin_rule` SET `deleted_at`=CURRENT_TIMESTAMP WHERE `deleted_at` IS NULL AND ",
+		},
+		{
+			name:       "26 sqlserver unique key",
+			driverName: "sqlserver",
+			opts:       []Option{WithColumnCount(1), WithUniqueIndex()},
+			got:        func(d dao) string { return d.sqlCreateTable },
+			want: "\nCREATE TABLE [casbin_rule](\n" +
+				"    [id] BIGINT IDENTITY(1,1) PRIMARY KEY,\n" +
+				"    [p_type] NVARCHAR(32) DEFAULT '' NOT NULL,\n" +
+				"    [v0] NVARCHAR(255) DEFAULT '' NOT NULL,\n" +
+				"    rule_key AS (HASHBYTES('SHA2_256',CONCAT_WS(NCHAR(31),[p_type],[v0]))) PERSISTED\n" +
+				");\n" +
+				"CREATE INDEX [idx_casbin_rule] ON [casbin_rule] ([p_type],[v0]);\n" +
+				"CREATE UNIQUE INDEX [uk_casbin_rule] ON [casbin_rule] (rule_key);",
+		},
+		{
+			name:       "27 sqlserver unique index migration",
+			driverName: "sqlserver",
+			opts:       []Option{WithColumnCount(1), WithUniqueIndex()},
+			got: func(d dao) string {
+				return strings.Join(d.uniqueIndexSteps(map[int]struct{}{primaryKeyMigrationVersion: {}}), ";\n")
+			},
+			want: "DELETE FROM [casbin_rule] WHERE [id] NOT IN (SELECT MIN([id]) FROM [casbin_rule] GROUP BY [p_type],[v0]);\n" +
+				"ALTER TABLE [casbin_rule] ADD rule_key AS (HASHBYTES('SHA2_256',CONCAT_WS(NCHAR(31),[p_type],[v0]))) PERSISTED;\n" +
+				"CREATE UNIQUE INDEX [uk_casbin_rule] ON [casbin_rule] (rule_key);",
+		},
+		{
+			name:       "28 oracle upper case catalog names",
+			driverName: "oracle",
+			opts:       []Option{WithSchema("app")},
+			got:        func(d dao) string { return fmt.Sprint(d.table, d.tableExistArgs, d.migrationTableExistArgs) },
+			want:       `"APP"."CASBIN_RULE"[APP CASBIN_RULE] [APP CASBIN_RULE_MIGRATIONS]`,
+		},
+		{
+			name:       "29 postgres resolve table",
+			driverName: "postgres",
+			got:        func(d dao) string { return fmt.Sprint(d.sqlTableExist, d.tableExistArgs, d.migrationTableExistArgs) },
+			want:       `SELECT 1 WHERE to_regclass($1) IS NOT NULL["casbin_rule"] ["casbin_rule_migrations"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := newOptions(tt.opts)
+			if err != nil {
+				t.Fatalf("test case[%s] failed, err: %v", tt.name, err)
+			}
+
+			dialect, err := LookupDialect(tt.driverName)
+			if err != nil {
+				t.Fatalf("test case[%s] failed, err: %v", tt.name, err)
+			}
+
+			d := newDao(nil, dialect, defaultTableName, opts)
+			if got := tt.got(d); got != tt.want {
+				t.Errorf("test case[%s] failed, got: %s, want: %s", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+// nolint: paralleltest
+func TestDaoWrapError(t *testing.T) {
+	dialect, err := LookupDialect("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := newDao(nil, dialect, defaultTableName, options{columnCount: defaultColumnCount})
+
+	err = d.wrapError(errors.New(`pq: duplicate key value violates unique constraint "uk_casbin_rule"`))
+
+	var duplicateErr *DuplicateRuleError
+	if !errors.As(err, &duplicateErr) {
+		t.Errorf("want *DuplicateRuleError, got: %v", err)
+	}
+
+	if err = d.wrapError(errors.New("pq: relation does not exist")); errors.As(err, &duplicateErr) {
+		t.Errorf("want the original error, got: %v", err)
+	}
+}
+
+// nolint: funlen,paralleltest
+func TestDaoInsertBatches(t *testing.T) {
+	tests := []struct {
+		name       string
+		driverName string
+		opts       []Option
+		rows       int
+		wantQuery  string
+		wantSizes  []int
+		wantLast   string
+		wantRest   int
+	}{
+		{
+			name:       "01 one batch",
+			driverName: "sqlserver",
+			opts:       []Option{WithColumnCount(1)},
+			rows:       3,
+			wantQuery:  "INSERT INTO [casbin_rule] ([p_type],[v0]) VALUES (@p1,@p2),(@p3,@p4),(@p5,@p6)",
+			wantSizes:  []int{6},
+		},
+		{
+			name:       "02 batch size",
+			driverName: "postgres",
+			opts:       []Option{WithColumnCount(1), WithBatchSize(2)},
+			rows:       5,
+			wantQuery:  `INSERT INTO "casbin_rule" ("p_type","v0") VALUES ($1,$2),($3,$4)`,
+			wantSizes:  []int{4, 4},
+			wantLast:   `INSERT INTO "casbin_rule" ("p_type","v0") VALUES ($1,$2)`,
+			wantRest:   2,
+		},
+		{
+			name:       "03 bind parameter limit",
+			driverName: "sqlite3",
+			rows:       300,
+			wantSizes:  []int{142 * 7, 142 * 7},
+			wantRest:   16 * 7,
+		},
+		{
+			name:       "04 insert rows limit",
+			driverName: "sqlserver",
+			opts:       []Option{WithColumnCount(1), WithBatchSize(5000)},
+			rows:       2000,
+			wantSizes:  []int{2 * 1000, 2 * 1000},
+		},
+		{
+			name:       "05 ignore duplicates",
+			driverName: "mysql",
+			opts:       []Option{WithColumnCount(1), WithIgnoreDuplicates()},
+			rows:       2,
+			wantQuery:  "INSERT INTO `casbin_rule` (`p_type`,`v0`) VALUES (?,?),(?,?) ON DUPLICATE KEY UPDATE `p_type`=`p_type`",
+			wantSizes:  []int{4},
+		},
+		{
+			name:       "06 ignore duplicates row by row",
+			driverName: "sqlserver",
+			opts:       []Option{WithColumnCount(1), WithIgnoreDuplicates()},
+			rows:       2,
+			wantSizes:  []int{2, 2},
+		},
+		{
+			name:       "07 row by row",
+			driverName: "oracle",
+			opts:       []Option{WithColumnCount(1)},
+			rows:       2,
+			wantQuery:  `INSERT INTO "CASBIN_RULE" ("P_TYPE","V0") VALUES (:1,:2)`,
+			wantSizes:  []int{2, 2},
+		},
+		{
+			name:       "08 sqlserver default width",
+			driverName: "sqlserver",
+			rows:       defaultBatchSize,
+			wantSizes:  []int{299 * 7, 299 * 7, 299 * 7},
+			wantRest:   103 * 7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect, err := LookupDialect(tt.driverName)
+			if err != nil {
+				t.Fatalf("test case[%s] failed, err: %v", tt.name, err)
+			}
+
+			d, _, err := prepareDao(nil, dialect, "", tt.opts)
+			if err != nil {
+				t.Fatalf("test case[%s] failed, err: %v", tt.name, err)
+			}
+
+			rows := make([][]interface{}, tt.rows)
+			for idx := range rows {
+				rows[idx] = make([]interface{}, len(d.columns))
+			}
+
+			query, batches, last := d.genInsertBatches(rows)
+			if tt.wantQuery != "" && query != tt.wantQuery {
+				t.Errorf("test case[%s] failed, got: %s, want: %s", tt.name, query, tt.wantQuery)
+			}
+
+			sizes := make([]int, len(batches))
+			for idx, batch := range batches {
+				sizes[idx] = len(batch)
+			}
+
+			if fmt.Sprint(sizes) != fmt.Sprint(tt.wantSizes) {
+				t.Errorf("test case[%s] failed, got batches: %v, want: %v", tt.name, sizes, tt.wantSizes)
+			}
+
+			if len(last.args) != tt.wantRest || (tt.wantRest == 0) != (last.query == "") {
+				t.Errorf("test case[%s] failed, got the last args: %d, want: %d", tt.name, len(last.args), tt.wantRest)
+			}
+
+			if tt.wantRest != 0 && strings.Count(last.query, "(") != tt.wantRest/len(d.columns)+1 {
+				t.Errorf("test case[%s] failed, got the last query: %s", tt.name, last.query)
+			}
+
+			if tt.wantLast != "" && last.query != tt.wantLast {
+				t.Errorf("test case[%s] failed, got: %s, want: %s", tt.name, last.query, tt.wantLast)
+			}
+		})
+	}
+}
+
+// nolint: funlen,paralleltest
+func TestDaoFilterCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		driverName string
+		filter     FilterEx
+		// or  the filters joined with filter by OR.
+		or        []FilterEx
+		wantQuery string
+		wantArgs  []interface{}
+		wantErr   bool
+	}{
+		{
+			name:       "01 equal and prefix",
+			driverName: "mysql",
+			filter:     FilterEx{PType: Equal("p"), V1: Prefix("/orgs/42_a/")},
+			wantQuery:  "`p_type`=? AND `v1` LIKE ? ESCAPE '!'",
+			wantArgs:   []interface{}{"p", "/orgs/42!_a/%"},
+		},
+		{
+			name:       "02 prefixes",
+			driverName: "postgres",
+			filter:     FilterEx{V0: Equal("alice", "bob"), V1: Prefix("/a/", "100%!")},
+			wantQuery:  `"v0" IN ($1,$2) AND ("v1" LIKE $3 ESCAPE '!' OR "v1" LIKE $4 ESCAPE '!')`,
+			wantArgs:   []interface{}{"alice", "bob", "/a/%", "100!%!!%"},
+		},
+		{
+			name:       "03 glob",
+			driverName: "sqlserver",
+			filter:     FilterEx{V1: Glob("/[a]/*/d?ta_1")},
+			wantQuery:  "[v1] LIKE @p1 ESCAPE '!'",
+			wantArgs:   []interface{}{"/![a]/%/d_ta!_1"},
+		},
+		{
+			name:       "04 like",
+			driverName: "oracle",
+			filter:     FilterEx{V1: Like("/orgs/%/data_"), V2: Like("[x]")},
+			wantQuery:  `"V1" LIKE :1 ESCAPE '!' AND "V2" LIKE :2 ESCAPE '!'`,
+			wantArgs:   []interface{}{"/orgs/%/data_", "[x]"},
+		},
+		{
+			name:       "05 sqlserver like brackets",
+			driverName: "sqlserver",
+			filter:     FilterEx{V0: Like("[x]%")},
+			wantQuery:  "[v0] LIKE @p1 ESCAPE '!'",
+			wantArgs:   []interface{}{"![x]%"},
+		},
+		{
+			name:       "06 not equal",
+			driverName: "postgres",
+			filter:     FilterEx{PType: Equal("p"), V0: NotEqual("system"), V1: NotEqual("data1", "data2")},
+			wantQuery:  `"p_type"=$1 AND "v0"<>$2 AND "v1" NOT IN ($3,$4)`,
+			wantArgs:   []interface{}{"p", "system", "data1", "data2"},
+		},
+		{
+			name:       "07 oracle not equal",
+			driverName: "oracle",
+			filter:     FilterEx{PType: NotEqual("g2"), V2: NotEqual("read", "write")},
+			wantQuery:  `("P_TYPE" IS NULL OR "P_TYPE"<>:1) AND ("V2" IS NULL OR "V2" NOT IN (:2,:3))`,
+			wantArgs:   []interface{}{"g2", "read", "write"},
+		},
+		{
+			name:       "08 or",
+			driverName: "sqlserver",
+			filter:     FilterEx{PType: Equal("p"), V1: Equal("domain1")},
+			or:         []FilterEx{{PType: Equal("g"), V2: Equal("domain1")}, {PType: Equal("p"), V1: Equal("domain1")}},
+			wantQuery:  "(([p_type]=@p1 AND [v1]=@p2) OR ([p_type]=@p3 AND [v2]=@p4))",
+			wantArgs:   []interface{}{"p", "domain1", "g", "domain1"},
+		},
+		{
+			name:       "09 or all",
+			driverName: "mysql",
+			filter:     FilterEx{PType: Equal("p")},
+			or:         []FilterEx{{}},
+		},
+		{
+			name:       "10 invalid operator",
+			driverName: "sqlite3",
+			filter:     FilterEx{V0: Condition{Op: -1, Values: []string{"a"}}},
+			wantErr:    true,
+		},
+		{
+			name:       "11 oracle empty value",
+			driverName: "oracle",
+			filter:     FilterEx{V2: Equal(""), V3: NotEqual("")},
+			wantQuery:  `"V2" IS NULL AND "V3" IS NOT NULL`,
+		},
+		{
+			name:       "12 oracle empty and values",
+			driverName: "oracle",
+			filter:     FilterEx{PType: Equal("p"), V1: NotEqual("", "a"), V2: Equal("", "read")},
+			wantQuery:  `"P_TYPE"=:1 AND "V1" IS NOT NULL AND "V1"<>:2 AND ("V2" IS NULL OR "V2"=:3)`,
+			wantArgs:   []interface{}{"p", "a", "read"},
+		},
+		{
+			name:       "13 empty value",
+			driverName: "sqlite3",
+			filter:     FilterEx{V2: Equal("")},
+			wantQuery:  `"v2"=?`,
+			wantArgs:   []interface{}{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect, err := LookupDialect(tt.driverName)
+			if err != nil {
+				t.Fatalf("test case[%s] failed, err: %v", tt.name, err)
+			}
+
+			d := newDao(nil, dialect, defaultTableName, options{columnCount: defaultColumnCount})
+
+			filters := [][]Condition{tt.filter.genData()}
+			for _, filter := range tt.or {
+				filters = append(filters, filter.genData())
+			}
+
+			query, args, err := d.genFilterCondition(filters)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("test case[%s] failed, err: %v, want error: %v", tt.name, err, tt.wantErr)
+			}
+
+			if query = d.rebindSQL(query); query != tt.wantQuery {
+				t.Errorf("test case[%s] failed, got: %s, want: %s", tt.name, query, tt.wantQuery)
+			}
+
+			if fmt.Sprint(args) != fmt.Sprint(tt.wantArgs) {
+				t.Errorf("test case[%s] failed, got args: %v, want: %v", tt.name, args, tt.wantArgs)
+			}
+		})
+	}
+}
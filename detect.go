@@ -0,0 +1,215 @@
+// Copyright 2026 by Blank-Xu. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqladapter
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// maxDriverWrapDepth  the maximum number of the wrapped drivers to unwrap.
+const maxDriverWrapDepth = 8
+
+// copyFromMethod  how the driver loads the rows by COPY FROM STDIN, see WithCopyFrom.
+type copyFromMethod int
+
+const (
+	// copyFromNone  the driver does not support COPY, the rows are inserted.
+	copyFromNone copyFromMethod = iota
+	// copyFromStmt  the driver prepares the COPY FROM STDIN statement of database/sql, e.g. lib/pq.
+	copyFromStmt
+	// copyFromPgConn  the driver connection has the pgconn.PgConn of pgx, see copyPgConn.
+	copyFromPgConn
+)
+
+// driverPackages  the package paths of the drivers, they are matched by the path prefix.
+var driverPackages = []struct {
+	pkgPath     string
+	dialectName string
+	copyFrom    copyFromMethod
+}{
+	{"modernc.org/sqlite", "sqlite3", copyFromNone},
+	{"github.com/mattn/go-sqlite3", "sqlite3", copyFromNone},
+	{"github.com/glebarez/go-sqlite", "sqlite3", copyFromNone},
+	{"github.com/ncruces/go-sqlite3", "sqlite3", copyFromNone},
+	{"github.com/go-sql-driver/mysql", "mysql", copyFromNone},
+	{"github.com/lib/pq", "postgres", copyFromStmt},
+	{"github.com/jackc/pgx", "postgres", copyFromPgConn},
+	{"github.com/microsoft/go-mssqldb", "sqlserver", copyFromNone},
+	{"github.com/denisenkom/go-mssqldb", "sqlserver", copyFromNone},
+	{"github.com/godror/godror", "oracle", copyFromNone},
+	{"github.com/sijms/go-ora", "oracle", copyFromNone},
+	{"github.com/mattn/go-oci8", "oracle", copyFromNone},
+}
+
+// versionProbes  the queries to detect the database, they are executed in order,
+// the first one which succeeds and returns a version with one of the keywords is the database.
+// The probes without keywords only need to succeed.
+var versionProbes = []struct {
+	query       string
+	keywords    []string
+	dialectName string
+}{
+	{sqlVersionSQLite3, nil, "sqlite3"},
+	{sqlVersion, []string{"PostgreSQL", "CockroachDB"}, "postgres"},
+	{sqlVersionSQLServer, []string{"Microsoft SQL Server"}, "sqlserver"},
+	{sqlVersionOracle, []string{"Oracle"}, "oracle"},
+	// VERSION() of MySQL only returns the version number, the system variable only exists in MySQL and MariaDB,
+	// its value depends on the distribution.
+	{sqlVersionMySQL, nil, "mysql"},
+}
+
+var driverInterface = reflect.TypeOf((*driver.Driver)(nil)).Elem()
+
+// DetectDialect  returns the Dialect of the database connected by db.
+// The driver of db is matched by the package path of the known drivers, the wrapped drivers are unwrapped,
+// e.g. the drivers registered by otelsql or sqlhooks.
+// Then the driver is matched by the driver names registered by sql.Register and RegisterDriverAlias.
+// At last, the database is detected by the version queries.
+func DetectDialect(ctx context.Context, db *sql.DB) (Dialect, error) {
+	if ctx == nil {
+		return nil, errors.New("ctx is nil")
+	}
+
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+
+	drv := db.Driver()
+
+	if idx := driverPackageOf(reflect.ValueOf(drv), 0); idx != -1 {
+		return LookupDialect(driverPackages[idx].dialectName)
+	}
+
+	if dialect := dialectOfDriverName(drv); dialect != nil {
+		return dialect, nil
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("%w of the driver %T, ping err: %v", ErrUnknownDialect, drv, err)
+	}
+
+	for _, probe := range versionProbes {
+		var version string
+		if err := db.QueryRowContext(ctx, probe.query).Scan(&version); err != nil {
+			continue
+		}
+
+		if len(probe.keywords) == 0 || containsAny(version, probe.keywords) {
+			return LookupDialect(probe.dialectName)
+		}
+	}
+
+	return nil, fmt.Errorf("%w of the driver %T, please use the constructors with the driver name", ErrUnknownDialect, drv)
+}
+
+// driverPackageOf returns the index of driverPackages of the driver, or of the wrapped driver.
+// It returns -1 if the driver is unknown.
+func driverPackageOf(value reflect.Value, depth int) int {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return -1
+		}
+
+		value = value.Elem()
+	}
+
+	pkgPath := value.Type().PkgPath()
+	for idx, item := range driverPackages {
+		if pkgPath == item.pkgPath || strings.HasPrefix(pkgPath, item.pkgPath+"/") {
+			return idx
+		}
+	}
+
+	if value.Kind() != reflect.Struct || depth >= maxDriverWrapDepth {
+		return -1
+	}
+
+	// the wrappers keep the driver in a field, the unexported fields can be read by reflect.
+	for idx := 0; idx < value.NumField(); idx++ {
+		field := value.Field(idx)
+		if field.Type() != driverInterface && !field.Type().Implements(driverInterface) {
+			continue
+		}
+
+		if idx := driverPackageOf(field, depth+1); idx != -1 {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// copyFromOf returns how the driver loads the rows by the COPY FROM STDIN statement.
+func copyFromOf(drv driver.Driver) copyFromMethod {
+	idx := driverPackageOf(reflect.ValueOf(drv), 0)
+	if idx == -1 {
+		return copyFromNone
+	}
+
+	return driverPackages[idx].copyFrom
+}
+
+// dialectOfDriverName returns the Dialect of the registered driver names which have the same driver type,
+// it returns nil if the driver names have different dialects.
+func dialectOfDriverName(drv driver.Driver) Dialect {
+	driverType := reflect.TypeOf(drv)
+
+	var result Dialect
+
+	for _, driverName := range sql.Drivers() {
+		dialect, err := LookupDialect(driverName)
+		if err != nil {
+			continue
+		}
+
+		// sql.Open does not connect to the database.
+		db, err := sql.Open(driverName, "")
+		if err != nil {
+			continue
+		}
+
+		sameType := reflect.TypeOf(db.Driver()) == driverType
+		_ = db.Close()
+
+		if !sameType {
+			continue
+		}
+
+		if result != nil && result.Name() != dialect.Name() {
+			return nil
+		}
+
+		result = dialect
+	}
+
+	return result
+}
+
+// containsAny returns true if s contains one of the keywords.
+func containsAny(s string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if strings.Contains(s, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 by Blank-Xu. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqladapter
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+)
+
+// testDriver  a driver which can not connect.
+type testDriver struct{}
+
+func (testDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("test driver can not connect")
+}
+
+type testConnector struct {
+	driver driver.Driver
+}
+
+func (c testConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open("")
+}
+
+func (c testConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// versionDriver  a driver which only answers the version queries.
+type versionDriver struct {
+	versions map[string]string
+}
+
+func (d versionDriver) Open(string) (driver.Conn, error) {
+	return versionConn(d), nil
+}
+
+type versionConn versionDriver
+
+func (c versionConn) Prepare(query string) (driver.Stmt, error) {
+	version, ok := c.versions[query]
+	if !ok {
+		return nil, errors.New("syntax error")
+	}
+
+	return versionStmt(version), nil
+}
+
+func (versionConn) Close() error { return nil }
+
+func (versionConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type versionStmt string
+
+func (versionStmt) Close() error { return nil }
+
+func (versionStmt) NumInput() int { return 0 }
+
+func (versionStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s versionStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &versionRows{version: string(s)}, nil
+}
+
+type versionRows struct {
+	version string
+	done    bool
+}
+
+func (*versionRows) Columns() []string { return []string{"version"} }
+
+func (*versionRows) Close() error { return nil }
+
+func (r *versionRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+
+	r.done = true
+	dest[0] = r.version
+
+	return nil
+}
+
+func TestDetectDialectByVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions map[string]string
+		want     string
+	}{
+		{
+			name:     "01 postgres",
+			versions: map[string]string{sqlVersion: "PostgreSQL 16.2 on x86_64-pc-linux-gnu"},
+			want:     "postgres",
+		},
+		{
+			name:     "02 mysql",
+			versions: map[string]string{sqlVersion: "8.0.36", sqlVersionMySQL: "MySQL Community Server - GPL"},
+			want:     "mysql",
+		},
+		{
+			name:     "03 mariadb",
+			versions: map[string]string{sqlVersion: "10.11.6-MariaDB", sqlVersionMySQL: "mariadb.org binary distribution"},
+			want:     "mysql",
+		},
+		{
+			name:     "04 unknown version",
+			versions: map[string]string{sqlVersion: "1.0.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sql.OpenDB(testConnector{driver: versionDriver{versions: tt.versions}})
+			defer db.Close()
+
+			dialect, err := DetectDialect(context.Background(), db)
+			if tt.want == "" {
+				if !errors.Is(err, ErrUnknownDialect) {
+					t.Errorf("test case[%s] failed, want ErrUnknownDialect, got: %v", tt.name, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("test case[%s] failed, err: %v", tt.name, err)
+			}
+
+			if dialect.Name() != tt.want {
+				t.Errorf("test case[%s] failed, got: %s, want: %s", tt.name, dialect.Name(), tt.want)
+			}
+		})
+	}
+}
+
+// nolint: paralleltest
+func TestDetectDialect(t *testing.T) {
+	db := sql.OpenDB(testConnector{driver: testDriver{}})
+	defer db.Close()
+
+	if _, err := DetectDialect(nil, db); err == nil { // nolint: staticcheck
+		t.Error("want an error for the nil context")
+	}
+
+	if _, err := DetectDialect(context.Background(), nil); err == nil {
+		t.Error("want an error for the nil db")
+	}
+
+	if _, err := DetectDialect(context.Background(), db); !errors.Is(err, ErrUnknownDialect) {
+		t.Errorf("want ErrUnknownDialect, got: %v", err)
+	}
+
+	if _, err := NewAdapterFromDB(db, ""); !errors.Is(err, ErrUnknownDialect) {
+		t.Errorf("want ErrUnknownDialect, got: %v", err)
+	}
+
+	if copyFromOf(db.Driver()) != copyFromNone {
+		t.Error("want the unknown driver without COPY FROM")
+	}
+}
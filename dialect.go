@@ -0,0 +1,621 @@
+// Copyright 2026 by Blank-Xu. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqladapter
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Dialect  describes the SQL of a database, it is registered by RegisterDialect.
+// A new Dialect can embed a built-in Dialect from LookupDialect, and override some methods.
+type Dialect interface {
+	// Name returns the database name, e.g. "postgres".
+	Name() string
+
+	// Placeholder returns the bind parameter of the position, the position starts with 1.
+	// E.g. "?" for MySQL, "$1" for PostgreSQL.
+	Placeholder(position int) string
+
+	// Quote returns the quoted identifier, the name only contains letters, digits and underscores.
+	Quote(name string) string
+
+	// Templates returns the SQL templates of the database.
+	Templates() Templates
+
+	// IsDuplicateError returns true if err is a unique constraint violation.
+	IsDuplicateError(err error) bool
+}
+
+// Templates  the SQL templates of a Dialect, they are formatted by fmt.Sprintf.
+// The identifiers in the parameters are quoted by Dialect.Quote,
+// the placeholders in the templates are "?", they are rebound by Dialect.Placeholder.
+// The optional templates can be empty.
+type Templates struct {
+	// Block  optional, wraps the create table statements, %[1]s is the statements.
+	// E.g. the PL/SQL block of Oracle, which can not execute multiple statements.
+	Block string
+
+	// CreateTable  create the table and the index, %[1]s is the table, %[2]s is the column definitions,
+	// %[3]s is the index columns, %[4]s is the index name, %[5]s is the index table.
+	CreateTable string
+
+	// Column  the rule column definition, %[1]s is the column name, %[2]d is the length.
+	Column string
+
+	// Check  optional, the column constraint, it has the same parameters as Column.
+	Check string
+
+	// PrimaryKey  the surrogate primary key definition, %[1]s is the id column, it is required by WithUniqueIndex.
+	PrimaryKey string
+
+	// UniqueKey  the unique constraint or the column of the RuleKey in the create table statement,
+	// %[1]s is the unique index name, %[2]s is the unique target, %[3]s is the index table.
+	// WithUniqueIndex requires UniqueKey or UniqueIndex.
+	UniqueKey string
+
+	// UniqueIndex  the unique index statement after the create table statement,
+	// it has the same parameters as UniqueKey, and %[4]s is the PartialIndex condition.
+	UniqueIndex string
+
+	// RuleKey  optional, the unique target is the expression of the rule columns instead of the columns,
+	// %[1]s is the rule columns.
+	RuleKey string
+
+	// LiveKey  optional, the unique target of WithSoftDelete, it is NULL for the deleted rules,
+	// %[1]s is the deleted_at column, %[2]s is the RuleKey expression, or each rule column if RuleKey is empty.
+	LiveKey string
+
+	// PartialIndex  optional, the condition of the unique index of WithSoftDelete, %[1]s is the deleted_at column.
+	PartialIndex string
+
+	// LowerUnquoted  the database folds the unquoted identifiers to lower case, e.g. PostgreSQL.
+	// If the table of the mixed case names does not exist, the table of the lower case names is used,
+	// it is created by the releases which did not quote the identifiers.
+	LowerUnquoted bool
+
+	// EmptyIsNull  the database stores the empty strings as NULL, e.g. Oracle,
+	// the empty values of the filters are matched by IS NULL.
+	EmptyIsNull bool
+
+	// UpperQuoted  the identifiers are quoted in upper case, e.g. Oracle, which folds the unquoted identifiers to upper case.
+	// So the names are case-insensitive, and the tables created by the releases which did not quote the identifiers are used.
+	UpperQuoted bool
+
+	// QualifyIndex  the index names are qualified by the schema.
+	QualifyIndex bool
+
+	// UnqualifiedIndexTable  the table in the create index statement is not qualified by the schema.
+	UnqualifiedIndexTable bool
+
+	// Timestamp  optional, the audit timestamp column definition, %[1]s is the column name.
+	// The default is the TIMESTAMP column.
+	Timestamp string
+
+	// DeletedAt  optional, the soft delete column definition, %[1]s is the column name.
+	// The default is the nullable TIMESTAMP column.
+	DeletedAt string
+
+	// AddColumn  optional, add a column to the existing table, %[1]s is the table, %[2]s is the column definition.
+	// The default is "ALTER TABLE %[1]s ADD COLUMN %[2]s".
+	AddColumn string
+
+	// AddTimestamp  optional, add a timestamp column without the default value, %[1]s is the table, %[2]s is the column,
+	// the existing rows are updated after the columns are added.
+	// If it is empty, the Timestamp columns are added by AddColumn.
+	AddTimestamp string
+
+	// AddPrimaryKey  optional, add the surrogate primary key to the existing table, %[1]s is the table, %[2]s is the id column.
+	// If it is empty, the table is rebuilt to add the primary key.
+	AddPrimaryKey string
+
+	// AddRuleKey  optional, add the column of the RuleKey to the existing table before the unique index,
+	// %[1]s is the table, %[2]s is the unique target. It is used if the UniqueIndex is on the column.
+	AddRuleKey string
+
+	// AddUniqueIndex  optional, add the unique index to the existing table,
+	// %[1]s is the table, %[2]s is the unique target, %[3]s is the unique index name.
+	// If it is empty, UniqueIndex is used.
+	AddUniqueIndex string
+
+	// DropUniqueIndex  optional, %[1]s is the unique index name, %[2]s is the table,
+	// %[3]s is the unique index name qualified by the schema.
+	// The default is "DROP INDEX IF EXISTS %[3]s".
+	DropUniqueIndex string
+
+	// DeleteDuplicateRows  optional, keep the first one of the duplicate rules,
+	// %[1]s is the table, %[2]s is the rule columns, %[3]s is the id column.
+	DeleteDuplicateRows string
+
+	// InsertRow  optional, the insert statement of the rules, %[1]s is the table, %[2]s is the insert columns,
+	// %[3]s is the values. The default is "INSERT INTO %[1]s (%[2]s) VALUES (%[3]s)".
+	InsertRow string
+
+	// UpdateRow  optional, the update statement of the rules and the soft delete,
+	// %[1]s is the table, %[2]s is the set list, %[3]s is the condition.
+	// The default is "UPDATE %[1]s SET %[2]s WHERE %[3]s".
+	UpdateRow string
+
+	// DeleteRow  optional, the delete statement of the rules, %[1]s is the table, %[2]s is the condition,
+	// the conditions of the filters are appended to it with an empty %[2]s.
+	// The default is "DELETE FROM %[1]s WHERE %[2]s".
+	DeleteRow string
+
+	// SelectAll  optional, the select statement of all the rules, %[1]s is the select columns, %[2]s is the table.
+	// The default is "SELECT %[1]s FROM %[2]s".
+	SelectAll string
+
+	// SelectWhere  optional, the select statement of the filtered rules, %[1]s is the select columns, %[2]s is the table,
+	// the conditions are appended to it. The default is "SELECT %[1]s FROM %[2]s WHERE ".
+	SelectWhere string
+
+	// InsertIgnore  optional, the insert statement skips the duplicate rules, it is used by WithIgnoreDuplicates,
+	// %[1]s is the table, %[2]s is the insert columns, %[3]s is the values, %[4]s is the match condition of the rule,
+	// %[5]s is the first column, %[6]s is the table name without schema, %[7]s is the unique index name without schema.
+	// The placeholders of %[3]s and %[4]s are rebound already, they have the same positions.
+	InsertIgnore string
+
+	// MultiRowInsert  the insert statement accepts multiple rows, e.g. "VALUES (?,?),(?,?)".
+	// If it is false, the rows are inserted one by one by a prepared statement.
+	MultiRowInsert bool
+
+	// MultiRowInsertIgnore  InsertIgnore accepts multiple rows in the values.
+	MultiRowInsertIgnore bool
+
+	// MaxInsertRows  optional, the maximum number of rows in a multi-row insert statement.
+	MaxInsertRows int
+
+	// MaxParams  optional, the maximum number of bind parameters in a statement.
+	MaxParams int
+
+	// CopyFrom  optional, the bulk load statement of SavePolicy, %[1]s is the table, %[2]s is the rule columns.
+	// The driver prepares it and executes it for each row, and then executes it without args to flush the rows,
+	// e.g. CopyIn of lib/pq, see WithCopyFrom.
+	CopyFrom string
+
+	// Limit  optional, limits the rows of the ordered select statement of WithPageSize, %[1]d is the number of rows.
+	// The default is " LIMIT %[1]d".
+	Limit string
+
+	// SnapshotIsolation  optional, the isolation level of the transaction which reads all the pages of WithSnapshot.
+	// The default is the default level of the database.
+	SnapshotIsolation sql.IsolationLevel
+
+	// Like  optional, the pattern condition of FilterEx, %[1]s is the column.
+	// The default is "%[1]s LIKE ? ESCAPE '!'", the patterns are escaped by '!'.
+	Like string
+
+	// LikeEscapes  optional, the wildcard characters of Like besides % and _, they are escaped in all the patterns.
+	LikeEscapes string
+
+	// Match  optional, the equality condition of a rule column, %[1]s is the column.
+	// The default is "%[1]s=?".
+	Match string
+
+	// NotMatch  optional, the negative condition of FilterEx, %[1]s is the column,
+	// %[2]s is "<>?" for a value, or " NOT IN (?,...)" for the values.
+	// The default is "%[1]s%[2]s", the nullable columns also match the NULL values.
+	NotMatch string
+
+	// TableExist  returns a row if the table exists, the last placeholder is the table name,
+	// %[1]s is the Schema or CurrentSchema expression, %[2]s is the quoted schema with a dot if Schema is empty.
+	TableExist string
+
+	// CurrentSchema  optional, the expression of the current schema in TableExist.
+	CurrentSchema string
+
+	// ResolveTable  optional, returns a row if the unqualified table name resolves to a table,
+	// as the other statements resolve it, e.g. by the search path of PostgreSQL.
+	// It is used instead of TableExist if no schema is given, the placeholder is the quoted table name.
+	ResolveTable string
+
+	// Schema  optional, the expression of the given schema in TableExist, it has a placeholder of the schema name.
+	// If it is empty, the schema is not a bind parameter.
+	Schema string
+
+	// CreateMigrationTable  optional, create the version table if it does not exist, %[1]s is the table.
+	CreateMigrationTable string
+
+	// Cutoff  the time which is the placeholder seconds earlier than now by the database clock,
+	// it is required by WithSoftDelete.
+	Cutoff string
+}
+
+// withDefaults returns the templates with the defaults of the optional templates.
+func (t Templates) withDefaults() Templates {
+	defaults := []struct {
+		template *string
+		value    string
+	}{
+		{&t.Timestamp, sqlTimestamp},
+		{&t.DeletedAt, sqlDeletedAt},
+		{&t.AddColumn, sqlAddColumn},
+		{&t.DropUniqueIndex, sqlDropUniqueIndex},
+		{&t.DeleteDuplicateRows, sqlDeleteDuplicateRow},
+		{&t.InsertRow, sqlInsertRow},
+		{&t.UpdateRow, sqlUpdateRow},
+		{&t.DeleteRow, sqlDeleteRow},
+		{&t.SelectAll, sqlSelectAll},
+		{&t.SelectWhere, sqlSelectWhere},
+		{&t.Match, sqlMatch},
+		{&t.NotMatch, sqlNotMatch},
+		{&t.Limit, sqlLimit},
+		{&t.Like, sqlLike},
+		{&t.CreateMigrationTable, sqlCreateMigrationTable},
+	}
+
+	for _, item := range defaults {
+		if *item.template == "" {
+			*item.template = item.value
+		}
+	}
+
+	return t
+}
+
+// validate check the required templates, includes the templates required by the options.
+func (t Templates) validate(o options) error {
+	var missing string
+
+	switch {
+	case t.CreateTable == "":
+		missing = "CreateTable"
+	case t.Column == "":
+		missing = "Column"
+	case t.TableExist == "":
+		missing = "TableExist"
+	case (o.uniqueIndex || o.pageSize > 0) && t.PrimaryKey == "":
+		missing = "PrimaryKey"
+	case o.uniqueIndex && t.UniqueKey == "" && t.UniqueIndex == "":
+		missing = "UniqueKey or UniqueIndex"
+	case o.softDelete && t.Cutoff == "":
+		missing = "Cutoff"
+	default:
+		return nil
+	}
+
+	return fmt.Errorf("the %s template is required", missing)
+}
+
+// builtinDialect  the built-in Dialect.
+type builtinDialect struct {
+	name string
+
+	// placeholder  "?", or the prefix of the numbered placeholders.
+	placeholder string
+
+	quotes [2]string
+
+	templates Templates
+
+	// duplicateErrors  the keywords of the unique constraint violation errors,
+	// the drivers are not imported, so the error messages are used.
+	duplicateErrors []string
+}
+
+func (d builtinDialect) Name() string {
+	return d.name
+}
+
+func (d builtinDialect) Placeholder(position int) string {
+	if d.placeholder == defaultPlaceholder {
+		return defaultPlaceholder
+	}
+
+	return d.placeholder + strconv.Itoa(position)
+}
+
+func (d builtinDialect) Quote(name string) string {
+	return d.quotes[0] + name + d.quotes[1]
+}
+
+func (d builtinDialect) Templates() Templates {
+	return d.templates
+}
+
+func (d builtinDialect) IsDuplicateError(err error) bool {
+	msg := err.Error()
+
+	for _, keyword := range d.duplicateErrors {
+		if strings.Contains(msg, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// the built-in dialects and their driver names.
+var builtinDialects = []struct {
+	dialect     builtinDialect
+	driverNames []string
+}{
+	{
+		dialect: builtinDialect{
+			name:        "sqlite3",
+			placeholder: defaultPlaceholder,
+			quotes:      [2]string{`"`, `"`},
+			templates: Templates{
+				CreateTable:           sqlCreateTableSQLite3,
+				Column:                sqlColumnDefSQLite3,
+				Check:                 sqlColumnCheckSQLite3,
+				PrimaryKey:            sqlPrimaryKeySQLite3,
+				UniqueIndex:           sqlUniqueIndexSQLite3,
+				PartialIndex:          sqlPartialIndex,
+				QualifyIndex:          true,
+				UnqualifiedIndexTable: true,
+				Timestamp:             sqlTimestamp,
+				DeletedAt:             sqlDeletedAt,
+				AddColumn:             sqlAddColumn,
+				AddTimestamp:          sqlAddTimestampSQLite3,
+				DropUniqueIndex:       sqlDropUniqueIndex,
+				DeleteDuplicateRows:   sqlDeleteDuplicateRow,
+				InsertIgnore:          sqlInsertIgnoreSQLite3,
+				MultiRowInsert:        true,
+				MultiRowInsertIgnore:  true,
+				// SQLite limits 999 bind parameters before 3.32.0, and 32766 since.
+				MaxParams:            999,
+				TableExist:           sqlTableExistSQLite3,
+				CreateMigrationTable: sqlCreateMigrationTable,
+				Cutoff:               sqlCutoffSQLite3,
+			},
+			duplicateErrors: []string{"UNIQUE constraint failed"},
+		},
+		driverNames: []string{"sqlite", "nrsqlite3"},
+	},
+	{
+		dialect: builtinDialect{
+			name:        "mysql",
+			placeholder: defaultPlaceholder,
+			quotes:      [2]string{"`", "`"},
+			templates: Templates{
+				CreateTable:          sqlCreateTableMySQL,
+				Column:               sqlColumnDefMySQL,
+				PrimaryKey:           sqlPrimaryKeyMySQL,
+				UniqueKey:            sqlUniqueKeyMySQL,
+				RuleKey:              sqlRuleKeyMySQL,
+				LiveKey:              sqlLiveRuleKeyMySQL,
+				Timestamp:            sqlTimestamp,
+				DeletedAt:            sqlDeletedAtMySQL,
+				AddColumn:            sqlAddColumn,
+				AddPrimaryKey:        sqlAddPrimaryKeyMySQL,
+				AddUniqueIndex:       sqlAddUniqueKeyMySQL,
+				DropUniqueIndex:      sqlDropUniqueKeyMySQL,
+				DeleteDuplicateRows:  sqlDeleteDuplicateRowMySQL,
+				InsertIgnore:         sqlInsertIgnoreMySQL,
+				MultiRowInsert:       true,
+				MultiRowInsertIgnore: true,
+				MaxParams:            65535,
+				SnapshotIsolation:    sql.LevelRepeatableRead,
+				TableExist:           sqlTableExist,
+				CurrentSchema:        sqlCurrentSchemaMySQL,
+				Schema:               defaultPlaceholder,
+				CreateMigrationTable: sqlCreateMigrationTable,
+				Cutoff:               sqlCutoffMySQL,
+			},
+			duplicateErrors: []string{"Error 1062", "Duplicate entry"},
+		},
+		driverNames: []string{"nrmysql"},
+	},
+	{
+		dialect: builtinDialect{
+			name:        "postgres",
+			placeholder: sqlPlaceholderPostgreSQL,
+			quotes:      [2]string{`"`, `"`},
+			templates: Templates{
+				CreateTable:          sqlCreateTablePostgreSQL,
+				Column:               sqlColumnDefPostgreSQL,
+				PrimaryKey:           sqlPrimaryKeyPostgreSQL,
+				UniqueIndex:          sqlUniqueIndexPostgreSQL,
+				PartialIndex:         sqlPartialIndex,
+				LowerUnquoted:        true,
+				Timestamp:            sqlTimestampPostgreSQL,
+				DeletedAt:            sqlDeletedAtPostgreSQL,
+				AddColumn:            sqlAddColumn,
+				AddPrimaryKey:        sqlAddPrimaryKeyPostgreSQL,
+				DropUniqueIndex:      sqlDropUniqueIndex,
+				DeleteDuplicateRows:  sqlDeleteDuplicateRow,
+				InsertIgnore:         sqlInsertIgnorePostgreSQL,
+				CopyFrom:             sqlCopyFromPostgreSQL,
+				MultiRowInsert:       true,
+				MultiRowInsertIgnore: true,
+				MaxParams:            65535,
+				SnapshotIsolation:    sql.LevelRepeatableRead,
+				TableExist:           sqlTableExist,
+				CurrentSchema:        sqlCurrentSchemaPostgreSQL,
+				ResolveTable:         sqlResolveTablePostgreSQL,
+				Schema:               defaultPlaceholder,
+				CreateMigrationTable: sqlCreateMigrationTable,
+				Cutoff:               sqlCutoffPostgreSQL,
+			},
+			duplicateErrors: []string{"23505", "duplicate key value violates unique constraint"},
+		},
+		driverNames: []string{"pgx", "pq-timeouts", "cloudsql-postgres", "ql", "nrpostgres", "cockroach"},
+	},
+	{
+		dialect: builtinDialect{
+			name:        "sqlserver",
+			placeholder: sqlPlaceholderSQLServer,
+			quotes:      [2]string{"[", "]"},
+			templates: Templates{
+				CreateTable:          sqlCreateTableSQLServer,
+				Column:               sqlColumnDefSQLServer,
+				PrimaryKey:           sqlPrimaryKeySQLServer,
+				UniqueKey:            sqlUniqueKeySQLServer,
+				UniqueIndex:          sqlUniqueIndexSQLServer,
+				RuleKey:              sqlRuleKeySQLServer,
+				PartialIndex:         sqlPartialIndex,
+				Timestamp:            sqlTimestampSQLServer,
+				DeletedAt:            sqlDeletedAtSQLServer,
+				AddColumn:            sqlAddColumnSQLServer,
+				AddPrimaryKey:        sqlAddPrimaryKeySQLServer,
+				AddRuleKey:           sqlAddRuleKeySQLServer,
+				DropUniqueIndex:      sqlDropIndexSQLServer,
+				DeleteDuplicateRows:  sqlDeleteDuplicateRow,
+				InsertIgnore:         sqlInsertIgnoreSQLServer,
+				MultiRowInsert:       true,
+				MaxInsertRows:        1000,
+				MaxParams:            maxParamsSQLServer,
+				Limit:                sqlLimitSQLServer,
+				LikeEscapes:          sqlLikeEscapesSQLServer,
+				SnapshotIsolation:    sql.LevelSnapshot,
+				TableExist:           sqlTableExistSQLServer,
+				CurrentSchema:        sqlCurrentSchemaSQLServer,
+				ResolveTable:         sqlResolveTableSQLServer,
+				Schema:               sqlSchemaSQLServer,
+				CreateMigrationTable: sqlCreateMigrationTableSQLServer,
+				Cutoff:               sqlCutoffSQLServer,
+			},
+			duplicateErrors: []string{"Cannot insert duplicate key"},
+		},
+		driverNames: []string{"azuresql"},
+	},
+	{
+		dialect: builtinDialect{
+			name:        "oracle",
+			placeholder: sqlPlaceholderOracle,
+			quotes:      [2]string{`"`, `"`},
+			templates: Templates{
+				Block:                sqlBlockOracle,
+				CreateTable:          sqlCreateTableOracle,
+				Column:               sqlColumnDefOracle,
+				PrimaryKey:           sqlPrimaryKeyOracle,
+				UniqueIndex:          sqlUniqueIndexOracle,
+				LiveKey:              sqlLiveColumnOracle,
+				QualifyIndex:         true,
+				UpperQuoted:          true,
+				EmptyIsNull:          true,
+				Timestamp:            sqlTimestamp,
+				DeletedAt:            sqlDeletedAt,
+				AddColumn:            sqlAddColumnOracle,
+				AddPrimaryKey:        sqlAddPrimaryKeyOracle,
+				DropUniqueIndex:      sqlDropIndexOracle,
+				DeleteDuplicateRows:  sqlDeleteDuplicateRow,
+				InsertIgnore:         sqlInsertIgnoreOracle,
+				Match:                sqlMatchOracle,
+				NotMatch:             sqlNotMatchOracle,
+				Limit:                sqlLimitOracle,
+				SnapshotIsolation:    sql.LevelSerializable,
+				TableExist:           sqlTableExistOracle,
+				CurrentSchema:        sqlCurrentSchemaOracle,
+				Schema:               defaultPlaceholder,
+				CreateMigrationTable: sqlCreateMigrationTableOracle,
+				Cutoff:               sqlCutoffOracle,
+			},
+			duplicateErrors: []string{"ORA-00001"},
+		},
+		driverNames: []string{"godror", "oci8", "ora", "goracle"},
+	},
+}
+
+var (
+	dialectsMu sync.RWMutex
+
+	// dialects  the registered dialects by name.
+	dialects = make(map[string]Dialect, len(builtinDialects))
+
+	// driverDialects  the driver names to the dialect names.
+	driverDialects = make(map[string]string, 32)
+)
+
+func init() {
+	for _, builtin := range builtinDialects {
+		if err := RegisterDialect(builtin.dialect.name, builtin.dialect); err != nil {
+			panic(err)
+		}
+
+		for _, driverName := range builtin.driverNames {
+			if err := RegisterDriverAlias(driverName, builtin.dialect.name); err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+// RegisterDialect  register a Dialect by name, the name is also a driver name of the Dialect.
+// The built-in dialects are "sqlite3", "mysql", "postgres", "sqlserver" and "oracle".
+func RegisterDialect(name string, dialect Dialect) error {
+	if name == "" {
+		return errors.New("sqladapter: the dialect name is empty")
+	}
+
+	if dialect == nil {
+		return fmt.Errorf("sqladapter: the dialect %s is nil", name)
+	}
+
+	if err := dialect.Templates().validate(options{}); err != nil {
+		return fmt.Errorf("sqladapter: invalid dialect %s: %w", name, err)
+	}
+
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+
+	if _, ok := dialects[name]; ok {
+		return fmt.Errorf("sqladapter: the dialect %s is registered already", name)
+	}
+
+	if _, ok := driverDialects[name]; ok {
+		return fmt.Errorf("sqladapter: the driver name %s is registered already", name)
+	}
+
+	dialects[name] = dialect
+	driverDialects[name] = name
+
+	return nil
+}
+
+// RegisterDriverAlias  use the registered dialect for the driver name,
+// e.g. RegisterDriverAlias("tidb", "mysql") or a driver name registered by an instrumented driver.
+func RegisterDriverAlias(driverName, dialectName string) error {
+	if driverName == "" {
+		return errors.New("sqladapter: the driver name is empty")
+	}
+
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+
+	if _, ok := dialects[dialectName]; !ok {
+		return fmt.Errorf("sqladapter: the dialect %s is not registered", dialectName)
+	}
+
+	if registered, ok := driverDialects[driverName]; ok && registered != dialectName {
+		return fmt.Errorf("sqladapter: the driver name %s is registered for the dialect %s already", driverName, registered)
+	}
+
+	driverDialects[driverName] = dialectName
+
+	return nil
+}
+
+// LookupDialect  returns the Dialect of the driver name.
+func LookupDialect(driverName string) (Dialect, error) {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+
+	if dialectName, ok := driverDialects[driverName]; ok {
+		return dialects[dialectName], nil
+	}
+
+	switch driverName {
+	case "mssql":
+		return nil, errors.New("driver name mssql not support, please use sqlserver")
+	default:
+		return nil, fmt.Errorf("unsupported driver name: %s", driverName)
+	}
+}
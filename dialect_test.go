@@ -0,0 +1,213 @@
+// Copyright 2026 by Blank-Xu. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqladapter
+
+import (
+	"strings"
+	"testing"
+)
+
+// testDialect  a custom dialect based on PostgreSQL.
+type testDialect struct {
+	Dialect
+}
+
+func (testDialect) Name() string {
+	return "testdb"
+}
+
+func (d testDialect) Templates() Templates {
+	t := d.Dialect.Templates()
+	t.InsertIgnore = ""
+
+	return t
+}
+
+// nolint: paralleltest
+func TestRegisterDialect(t *testing.T) {
+	postgres, err := LookupDialect("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		register func() error
+		wantErr  bool
+	}{
+		{
+			name:     "01 empty name",
+			register: func() error { return RegisterDialect("", postgres) },
+			wantErr:  true,
+		},
+		{
+			name:     "02 nil dialect",
+			register: func() error { return RegisterDialect("testdb", nil) },
+			wantErr:  true,
+		},
+		{
+			name:     "03 invalid templates",
+			register: func() error { return RegisterDialect("testdb", dialectWithoutTemplates{postgres}) },
+			wantErr:  true,
+		},
+		{
+			name:     "04 register",
+			register: func() error { return RegisterDialect("testdb", testDialect{postgres}) },
+		},
+		{
+			name:     "05 registered dialect",
+			register: func() error { return RegisterDialect("testdb", testDialect{postgres}) },
+			wantErr:  true,
+		},
+		{
+			name:     "06 registered driver name",
+			register: func() error { return RegisterDialect("pgx", testDialect{postgres}) },
+			wantErr:  true,
+		},
+		{
+			name:     "07 alias",
+			register: func() error { return RegisterDriverAlias("testdb-driver", "testdb") },
+		},
+		{
+			name:     "08 alias again",
+			register: func() error { return RegisterDriverAlias("testdb-driver", "testdb") },
+		},
+		{
+			name:     "09 conflict alias",
+			register: func() error { return RegisterDriverAlias("testdb-driver", "mysql") },
+			wantErr:  true,
+		},
+		{
+			name:     "10 unknown dialect",
+			register: func() error { return RegisterDriverAlias("db2", "db2") },
+			wantErr:  true,
+		},
+		{
+			name:     "11 empty driver name",
+			register: func() error { return RegisterDriverAlias("", "testdb") },
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.register(); (err != nil) != tt.wantErr {
+				t.Errorf("test case[%s] failed, err: %v, wantErr: %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+
+	query, err := CreateTableSQL("testdb-driver", "", WithColumnCount(1), WithIgnoreDuplicates())
+	if err != nil {
+		t.Fatalf("CreateTableSQL failed, err: %v", err)
+	}
+
+	if !strings.Contains(query, `CREATE TABLE IF NOT EXISTS "casbin_rule"`) {
+		t.Errorf("CreateTableSQL failed, got: %s", query)
+	}
+
+	dialect, err := LookupDialect("testdb")
+	if err != nil {
+		t.Fatalf("LookupDialect failed, err: %v", err)
+	}
+
+	dao, _, err := prepareDao(nil, dialect, "", []Option{WithColumnCount(1), WithIgnoreDuplicates()})
+	if err != nil {
+		t.Fatalf("prepareDao failed, err: %v", err)
+	}
+
+	if want := `INSERT INTO "casbin_rule" ("p_type","v0") VALUES ($1,$2)`; dao.sqlInsertRow != want {
+		t.Errorf("custom dialect failed, got: %s, want: %s", dao.sqlInsertRow, want)
+	}
+}
+
+// dialectWithoutTemplates  a dialect without the required templates.
+type dialectWithoutTemplates struct {
+	Dialect
+}
+
+func (dialectWithoutTemplates) Templates() Templates {
+	return Templates{}
+}
+
+// crudDialect  a dialect with the custom statements of the rules.
+type crudDialect struct {
+	Dialect
+}
+
+func (d crudDialect) Templates() Templates {
+	t := d.Dialect.Templates()
+	t.InsertRow = "INSERT INTO %[1]s (%[2]s) OVERRIDING SYSTEM VALUE VALUES (%[3]s)"
+	t.DeleteRow = "DELETE FROM ONLY %[1]s WHERE %[2]s"
+	t.SelectWhere = "SELECT %[1]s FROM ONLY %[2]s WHERE "
+
+	return t
+}
+
+// nolint: paralleltest
+func TestCustomRowTemplates(t *testing.T) {
+	postgres, err := LookupDialect("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dao, _, err := prepareDao(nil, crudDialect{postgres}, "", []Option{WithColumnCount(1)})
+	if err != nil {
+		t.Fatalf("prepareDao failed, err: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{
+			name: "01 insert",
+			got:  dao.sqlInsertRow,
+			want: `INSERT INTO "casbin_rule" ("p_type","v0") OVERRIDING SYSTEM VALUE VALUES ($1,$2)`,
+		},
+		{
+			name: "02 delete",
+			got:  dao.sqlDeleteRow,
+			want: `DELETE FROM ONLY "casbin_rule" WHERE "p_type"=$1 AND "v0"=$2`,
+		},
+		{
+			name: "03 delete where",
+			got:  dao.sqlDeleteWhere,
+			want: `DELETE FROM ONLY "casbin_rule" WHERE `,
+		},
+		{
+			name: "04 select where",
+			got:  dao.sqlSelectWhere,
+			want: `SELECT "p_type","v0" FROM ONLY "casbin_rule" WHERE `,
+		},
+		{
+			name: "05 default update",
+			got:  dao.sqlUpdateRow,
+			want: `UPDATE "casbin_rule" SET "p_type"=$1,"v0"=$2 WHERE "p_type"=$3 AND "v0"=$4`,
+		},
+		{
+			name: "06 default select",
+			got:  dao.sqlSelectAll,
+			want: `SELECT "p_type","v0" FROM "casbin_rule"`,
+		},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("test case[%s] failed, got: %s, want: %s", tt.name, tt.got, tt.want)
+		}
+	}
+}
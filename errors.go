@@ -0,0 +1,77 @@
+// Copyright 2026 by Blank-Xu. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqladapter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTableNotExist  returned by the constructors when the table does not exist, and the Adapter is created WithoutDDL.
+var ErrTableNotExist = errors.New("sqladapter: table does not exist")
+
+// ErrTimestampsNotEnabled  returned by Adapter.LoadAuditedRules when the Adapter is created without WithTimestamps.
+var ErrTimestampsNotEnabled = errors.New("sqladapter: timestamps are not enabled")
+
+// ErrSoftDeleteNotEnabled  returned by Adapter.PurgeDeleted when the Adapter is created without WithSoftDelete.
+var ErrSoftDeleteNotEnabled = errors.New("sqladapter: soft delete is not enabled")
+
+// ErrUnknownDialect  returned by DetectDialect when the database can not be detected.
+var ErrUnknownDialect = errors.New("sqladapter: can not detect the dialect")
+
+// ErrRuleOutOfFilter  returned by Adapter.SavePolicy when the policy is loaded by a filter and a rule does not match it,
+// the rules out of the filter are kept by the save, so the rule would be inserted again by every save.
+var ErrRuleOutOfFilter = errors.New("sqladapter: rule does not match the filter")
+
+// DuplicateRuleError  returned when a rule violates the unique index of the table, see WithUniqueIndex.
+type DuplicateRuleError struct {
+	Err error
+}
+
+func (e *DuplicateRuleError) Error() string {
+	return "duplicate policy rule: " + e.Err.Error()
+}
+
+func (e *DuplicateRuleError) Unwrap() error {
+	return e.Err
+}
+
+// PendingMigrationsError  returned by the constructors when the table has the pending migrations of the options,
+// and the Adapter is created without WithAutoMigrate. The migrations can be applied by MigrateTable.
+type PendingMigrationsError struct {
+	Table      string
+	Migrations []Migration
+}
+
+func (e *PendingMigrationsError) Error() string {
+	versions := make([]string, 0, len(e.Migrations))
+	for _, m := range e.Migrations {
+		versions = append(versions, fmt.Sprintf("%d (%s)", m.Version, m.Description))
+	}
+
+	return fmt.Sprintf("sqladapter: the table %s has pending migrations: %s, apply them WithAutoMigrate or by MigrateTable",
+		e.Table, strings.Join(versions, ", "))
+}
+
+// InvalidFilterError  returned by Adapter.LoadFilteredPolicy when the type of the filter is not supported.
+type InvalidFilterError struct {
+	// Type  the type of the filter, e.g. "map[string]string".
+	Type string
+}
+
+func (e *InvalidFilterError) Error() string {
+	return fmt.Sprintf("sqladapter: invalid filter type: %s", e.Type)
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 by Blank-Xu. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqladapter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// identifierPattern  the allowed table, schema and column names.
+// The names are always quoted in SQL, so they are case-sensitive.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateIdentifier check the name is a valid identifier, kind is used in the error message.
+func validateIdentifier(kind, name string) error {
+	if len(name) > maxIdentifierLength {
+		return fmt.Errorf("invalid %s %q: longer than %d characters", kind, name, maxIdentifierLength)
+	}
+
+	if !identifierPattern.MatchString(name) {
+		return fmt.Errorf("invalid %s %q: only letters, digits and underscores are allowed, and it can not start with a digit", kind, name)
+	}
+
+	return nil
+}
+
+// splitTableName split "schema.table" to schema and table name, if schema is not given.
+func splitTableName(schema, tableName string) (string, string) {
+	if schema == "" {
+		if idx := strings.IndexByte(tableName, '.'); idx != -1 {
+			return tableName[:idx], tableName[idx+1:]
+		}
+	}
+
+	return schema, tableName
+}
+
+// validateTableName check the schema and the table name, includes the names derived from the table name.
+func validateTableName(schema, tableName string) error {
+	if schema != "" {
+		if err := validateIdentifier("schema name", schema); err != nil {
+			return err
+		}
+	}
+
+	names := []string{tableName, indexPrefix + tableName, uniqueIndexPrefix + tableName, tableName + migrationTableSuffix}
+	for _, name := range names {
+		if err := validateIdentifier("table name", name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
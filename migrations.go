@@ -0,0 +1,474 @@
+// Copyright 2026 by Blank-Xu. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqladapter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Migration  a versioned schema upgrade of the policy table.
+type Migration struct {
+	Version     int
+	Description string
+}
+
+type migration struct {
+	Migration
+
+	// enabled  returns true if the migration is required by the Adapter options.
+	enabled func(d dao) bool
+
+	// steps  returns the SQL statements for the connected database, they are executed in order.
+	// applied  the versions applied to the table before the migration.
+	steps func(d dao, applied map[int]struct{}) []string
+}
+
+// the migration versions referenced by the other migrations.
+const (
+	// baseMigrationVersion  the version of the table created by the releases without migrations.
+	baseMigrationVersion = 1
+
+	uniqueIndexMigrationVersion = 2
+	timestampsMigrationVersion  = 3
+	softDeleteMigrationVersion  = 4
+	primaryKeyMigrationVersion  = 5
+)
+
+// migrations  all the schema migrations, ordered by version.
+// The versions must never be changed after released.
+var migrations = []migration{
+	{
+		Migration: Migration{Version: baseMigrationVersion, Description: "create the policy table"},
+		enabled:   func(dao) bool { return true },
+		steps:     func(d dao, _ map[int]struct{}) []string { return []string{d.sqlCreateTable} },
+	},
+	{
+		Migration: Migration{Version: uniqueIndexMigrationVersion, Description: "add the surrogate primary key and the unique rule index"},
+		enabled:   func(d dao) bool { return d.uniqueIndex },
+		steps:     dao.uniqueIndexSteps,
+	},
+	{
+		Migration: Migration{Version: timestampsMigrationVersion, Description: "add the audit timestamp columns"},
+		enabled:   func(d dao) bool { return d.timestamps },
+		steps:     dao.timestampsSteps,
+	},
+	{
+		Migration: Migration{Version: softDeleteMigrationVersion, Description: "add the soft delete column"},
+		enabled:   func(d dao) bool { return d.softDelete },
+		steps:     dao.softDeleteSteps,
+	},
+	{
+		Migration: Migration{Version: primaryKeyMigrationVersion, Description: "add the surrogate primary key"},
+		// the unique index migration adds the primary key too.
+		enabled: func(d dao) bool { return d.primaryKey && !d.uniqueIndex },
+		steps:   dao.primaryKeySteps,
+	},
+}
+
+// appliedDao returns the dao with the optional columns of the applied migrations,
+// it describes the existing table.
+func (d dao) appliedDao(applied map[int]struct{}) dao {
+	_, d.uniqueIndex = applied[uniqueIndexMigrationVersion]
+	_, d.primaryKey = applied[primaryKeyMigrationVersion]
+	d.primaryKey = d.primaryKey || d.uniqueIndex
+	_, d.timestamps = applied[timestampsMigrationVersion]
+	_, d.softDelete = applied[softDeleteMigrationVersion]
+
+	return d
+}
+
+// uniqueIndexSteps  the duplicate rules are removed before creating the unique index.
+// The soft deleted rules are only merged with the rules deleted at the same time.
+func (d dao) uniqueIndexSteps(applied map[int]struct{}) []string {
+	current := d.appliedDao(applied)
+	hasPrimaryKey := current.primaryKey
+	current.uniqueIndex = true
+	current.primaryKey = true
+
+	columnList := strings.Join(d.columns, ",")
+
+	groupList := columnList
+	if current.softDelete {
+		groupList += "," + d.deletedAtColumn
+	}
+
+	t := d.templates
+
+	if t.AddPrimaryKey == "" {
+		// e.g. SQLite can not add a primary key to an existing table, so the table is rebuilt.
+		// The rebuilt table only has the optional columns which are added already,
+		// the timestamps of the duplicate rules are merged.
+		oldTableName := d.tableName + "_v2"
+
+		insertList, selectList := groupList, groupList
+		if current.timestamps {
+			insertList += "," + d.createdAtColumn + "," + d.updatedAtColumn
+			selectList += ",MIN(" + d.createdAtColumn + "),MAX(" + d.updatedAtColumn + ")"
+		}
+
+		return []string{
+			fmt.Sprintf(sqlRenameTable, d.table, d.quote(oldTableName)),
+			fmt.Sprintf(sqlDropIndex, d.indexName(indexPrefix)),
+			current.genCreateTableSQL(),
+			fmt.Sprintf(sqlCopyGroupedRows, d.table, d.qualify(oldTableName), insertList, selectList, groupList),
+			fmt.Sprintf(sqlDropTable, d.qualify(oldTableName)),
+		}
+	}
+
+	steps := make([]string, 0, 4)
+	if !hasPrimaryKey {
+		steps = append(steps, fmt.Sprintf(t.AddPrimaryKey, d.table, d.idColumn))
+	}
+
+	steps = append(steps, fmt.Sprintf(t.DeleteDuplicateRows, d.table, groupList, d.idColumn))
+
+	if t.AddRuleKey != "" {
+		steps = append(steps, fmt.Sprintf(t.AddRuleKey, d.table, current.uniqueTarget()))
+	}
+
+	return append(steps, current.addUniqueIndexSQL())
+}
+
+// primaryKeySteps  the primary key is added without the unique index, the duplicate rules are kept.
+func (d dao) primaryKeySteps(applied map[int]struct{}) []string {
+	current := d.appliedDao(applied)
+	if current.primaryKey {
+		return nil
+	}
+
+	current.primaryKey = true
+
+	if d.templates.AddPrimaryKey != "" {
+		return []string{fmt.Sprintf(d.templates.AddPrimaryKey, d.table, d.idColumn)}
+	}
+
+	// the table is rebuilt as the unique index migration does.
+	oldTableName := d.tableName + "_v5"
+
+	copyList := strings.Join(d.columns, ",")
+	if current.timestamps {
+		copyList += "," + d.createdAtColumn + "," + d.updatedAtColumn
+	}
+
+	if current.softDelete {
+		copyList += "," + d.deletedAtColumn
+	}
+
+	return []string{
+		fmt.Sprintf(sqlRenameTable, d.table, d.quote(oldTableName)),
+		fmt.Sprintf(sqlDropIndex, d.indexName(indexPrefix)),
+		current.genCreateTableSQL(),
+		fmt.Sprintf(sqlCopyRows, d.table, d.qualify(oldTableName), copyList),
+		fmt.Sprintf(sqlDropTable, d.qualify(oldTableName)),
+	}
+}
+
+// timestampsSteps  the existing rules get the time of the migration.
+func (d dao) timestampsSteps(map[int]struct{}) []string {
+	t := d.templates
+
+	if t.AddTimestamp != "" {
+		return []string{
+			fmt.Sprintf(t.AddTimestamp, d.table, d.createdAtColumn),
+			fmt.Sprintf(t.AddTimestamp, d.table, d.updatedAtColumn),
+			fmt.Sprintf(sqlSetTimestamps, d.table, d.createdAtColumn, d.updatedAtColumn),
+		}
+	}
+
+	return []string{
+		fmt.Sprintf(t.AddColumn, d.table, strings.TrimSpace(fmt.Sprintf(t.Timestamp, d.createdAtColumn))),
+		fmt.Sprintf(t.AddColumn, d.table, strings.TrimSpace(fmt.Sprintf(t.Timestamp, d.updatedAtColumn))),
+	}
+}
+
+// softDeleteSteps  the unique index is recreated for the rules which are not deleted.
+func (d dao) softDeleteSteps(applied map[int]struct{}) []string {
+	steps := []string{
+		fmt.Sprintf(d.templates.AddColumn, d.table, strings.TrimSpace(fmt.Sprintf(d.templates.DeletedAt, d.deletedAtColumn))),
+	}
+
+	if _, ok := applied[uniqueIndexMigrationVersion]; !ok {
+		return steps
+	}
+
+	current := d.appliedDao(applied)
+	current.softDelete = true
+
+	return append(steps, current.dropUniqueIndexSQL(), current.addUniqueIndexSQL())
+}
+
+// enabledMigrations returns the migrations required by the dao.
+func (d dao) enabledMigrations() []migration {
+	result := make([]migration, 0, len(migrations))
+
+	for _, m := range migrations {
+		if m.enabled(d) {
+			result = append(result, m)
+		}
+	}
+
+	return result
+}
+
+// CreateMigrationTable create the version table if it does not exist.
+func (d dao) CreateMigrationTable(ctx context.Context) error {
+	return d.execDDL(ctx, d.sqlCreateMigrationTable)
+}
+
+// SelectMigrationVersions select the applied migration versions.
+func (d dao) SelectMigrationVersions(ctx context.Context) (map[int]struct{}, error) {
+	rows, err := d.db.QueryContext(ctx, d.sqlSelectMigrations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make(map[int]struct{}, len(migrations))
+
+	for rows.Next() {
+		var version int
+		if err = rows.Scan(&version); err != nil {
+			return nil, err
+		}
+
+		versions[version] = struct{}{}
+	}
+
+	return versions, rows.Err()
+}
+
+// InsertMigrations record the migrations as applied without executing them in one transaction.
+// If reset is true, the recorded versions are deleted before, they belong to a dropped table.
+// The versions recorded by another Adapter at the same time are regarded as success.
+func (d dao) InsertMigrations(ctx context.Context, list []migration, reset bool) error {
+	tx, err := d.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx err: %w", err)
+	}
+
+	if reset {
+		_, err = tx.ExecContext(ctx, d.sqlDeleteMigrations)
+	}
+
+	for idx := 0; idx < len(list) && err == nil; idx++ {
+		_, err = tx.ExecContext(ctx, d.sqlInsertMigration, list[idx].Version, list[idx].Description)
+	}
+
+	if err == nil {
+		if err = tx.Commit(); err != nil {
+			return fmt.Errorf("record migrations commit err: %w", err)
+		}
+
+		return nil
+	}
+
+	// the migration errors are not wrapped by wrapError, they are not the duplicate rules.
+	if err1 := tx.Rollback(); err1 != nil {
+		return fmt.Errorf("record migrations err: %w, rollback err: %w", err, err1)
+	}
+
+	if d.isMigrationsApplied(ctx, list) {
+		return nil
+	}
+
+	return fmt.Errorf("record migrations err: %w", err)
+}
+
+// isMigrationsApplied returns true if all the migrations are recorded,
+// e.g. by another Adapter which migrates the same table at the same time.
+func (d dao) isMigrationsApplied(ctx context.Context, list []migration) bool {
+	versions, err := d.SelectMigrationVersions(ctx)
+	if err != nil {
+		return false
+	}
+
+	for _, m := range list {
+		if _, ok := versions[m.Version]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Provision create the table and the version table, and record all the enabled migrations,
+// because the new table has the current schema.
+// The versions left by a dropped table are deleted, they do not describe the new table.
+func (d dao) Provision(ctx context.Context) error {
+	if err := d.CreateTable(ctx); err != nil {
+		return err
+	}
+
+	if err := d.CreateMigrationTable(ctx); err != nil {
+		return err
+	}
+
+	return d.InsertMigrations(ctx, d.enabledMigrations(), true)
+}
+
+// ProvisionSQL returns the SQL statements executed by Provision.
+func (d dao) ProvisionSQL() []string {
+	list := d.enabledMigrations()
+
+	result := make([]string, 0, len(list)+3)
+	result = append(result, strings.TrimSpace(d.sqlCreateTable), strings.TrimSpace(d.sqlCreateMigrationTable),
+		d.sqlDeleteMigrations+";")
+
+	for _, m := range list {
+		result = append(result, fmt.Sprintf(sqlInsertMigrationValues, d.migrationTable, m.Version, m.Description))
+	}
+
+	return result
+}
+
+// ApplyMigration execute the migration steps and record the version in one transaction.
+// applied  the versions applied to the table before the migration.
+// Note: MySQL and Oracle commit the DDL statements implicitly, so a failed migration may be partially applied.
+func (d dao) ApplyMigration(ctx context.Context, m migration, applied map[int]struct{}) error {
+	tx, err := d.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx err: %w", err)
+	}
+
+	for _, query := range m.steps(d, applied) {
+		if _, err = tx.ExecContext(ctx, query); err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		_, err = tx.ExecContext(ctx, d.sqlInsertMigration, m.Version, m.Description)
+	}
+
+	if err != nil {
+		if err1 := tx.Rollback(); err1 != nil {
+			return fmt.Errorf("migration %d err: %w, rollback err: %w", m.Version, err, err1)
+		}
+
+		// another Adapter applied the migration at the same time, e.g. the column exists already.
+		if d.isMigrationsApplied(ctx, []migration{m}) {
+			return nil
+		}
+
+		return fmt.Errorf("migration %d err: %w", m.Version, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("migration %d commit err: %w", m.Version, err)
+	}
+
+	return nil
+}
+
+// pendingMigrations returns the enabled migrations which are not applied, ordered by version,
+// and the applied versions.
+// If the version table is empty or does not exist, the existing table is regarded as the base version,
+// and recordBase is true. The version table is not created, it is created by Migrate.
+func (d dao) pendingMigrations(ctx context.Context) (pending []migration, versions map[int]struct{}, recordBase bool, err error) {
+	exist, err := d.IsMigrationTableExist(ctx)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	versions = make(map[int]struct{}, len(migrations))
+
+	if exist {
+		if versions, err = d.SelectMigrationVersions(ctx); err != nil {
+			return nil, nil, false, err
+		}
+	}
+
+	if len(versions) == 0 {
+		recordBase = true
+		versions[baseMigrationVersion] = struct{}{}
+	}
+
+	list := d.enabledMigrations()
+	pending = make([]migration, 0, len(list))
+
+	for _, m := range list {
+		if _, ok := versions[m.Version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+
+	return pending, versions, recordBase, nil
+}
+
+// PendingMigrations returns the schema migrations which are not applied to the table yet.
+// It only reads the version table, all the migrations are pending if the version table does not exist.
+func (d dao) PendingMigrations(ctx context.Context) ([]Migration, error) {
+	pending, _, _, err := d.pendingMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return publicMigrations(pending), nil
+}
+
+// publicMigrations returns the Migration of the list.
+func publicMigrations(list []migration) []Migration {
+	result := make([]Migration, 0, len(list))
+	for _, m := range list {
+		result = append(result, m.Migration)
+	}
+
+	return result
+}
+
+// checkMigrations  returns *PendingMigrationsError if the table has the pending migrations,
+// the writes may fail on the table without the columns or the index of the options.
+func (adapter Adapter) checkMigrations(ctx context.Context) error {
+	pending, err := adapter.dao.PendingMigrations(ctx)
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+
+	return &PendingMigrationsError{Table: adapter.dao.table, Migrations: pending}
+}
+
+// Migrate applies the pending schema migrations in order,
+// each migration is applied in a transaction with its version record.
+// The version table is created if it does not exist.
+// The migrations applied by another Adapter at the same time are skipped,
+// but MySQL and Oracle commit the DDL statements implicitly, so the concurrent migrations may fail there.
+func (adapter Adapter) Migrate(ctx context.Context) error {
+	if err := adapter.dao.CreateMigrationTable(ctx); err != nil {
+		return err
+	}
+
+	pending, versions, recordBase, err := adapter.dao.pendingMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	if recordBase {
+		if err = adapter.dao.InsertMigrations(ctx, migrations[:1], false); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range pending {
+		if err = adapter.dao.ApplyMigration(ctx, m, versions); err != nil {
+			return err
+		}
+
+		versions[m.Version] = struct{}{}
+	}
+
+	return nil
+}
@@ -14,23 +14,33 @@
 
 package sqladapter
 
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
 // rule define the casbin rule model.
 // It used for save or load policy lines from connected database.
 type rule struct {
 	PType string
-	V0    string
-	V1    string
-	V2    string
-	V3    string
-	V4    string
-	V5    string
+
+	// Values  the v0, v1, ... columns, its length is the column count of the Adapter.
+	Values []string
 }
 
 func (rule rule) Data() []string {
-	s := []string{rule.PType, rule.V0, rule.V1, rule.V2, rule.V3, rule.V4, rule.V5}
-	data := make([]string, 0, maxParameterCount)
+	data := make([]string, 0, len(rule.Values)+1)
+
+	if rule.PType == "" {
+		return data
+	}
+
+	data = append(data, rule.PType)
 
-	for _, val := range s {
+	for _, val := range rule.Values {
 		if val == "" {
 			break
 		}
@@ -40,6 +50,66 @@ func (rule rule) Data() []string {
 	return data
 }
 
+// AuditedRule  a policy rule with the audit timestamps, see WithTimestamps.
+type AuditedRule struct {
+	PType string
+	Rule  []string
+
+	// CreatedAt and UpdatedAt  are set by the database clock.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// SaveResult  the number of the rules changed by Adapter.SavePolicyDiff.
+type SaveResult struct {
+	Added   int
+	Removed int
+}
+
+// the text layouts of the timestamps, they are used when the driver does not parse the timestamps,
+// e.g. MySQL without parseTime=true.
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+}
+
+// nullTime  scans the timestamp from time.Time or the text, NULL is scanned as the zero time.
+type nullTime struct {
+	Time time.Time
+}
+
+// Scan implements the sql.Scanner interface.
+func (t *nullTime) Scan(value interface{}) error {
+	var text string
+
+	switch v := value.(type) {
+	case nil:
+		t.Time = time.Time{}
+		return nil
+	case time.Time:
+		t.Time = v
+		return nil
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	default:
+		return fmt.Errorf("unsupported timestamp type: %T", value)
+	}
+
+	for _, layout := range timeLayouts {
+		parsed, err := time.Parse(layout, text)
+		if err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid timestamp: %q", text)
+}
+
 // Filter define the filtering rules for a FilteredAdapter's policy.
 // Empty values are ignored, but all others must match the Filter.
 type Filter struct {
@@ -50,21 +120,197 @@ type Filter struct {
 	V3    []string
 	V4    []string
 	V5    []string
+
+	// Extra  the filtering rules for the columns after v5, it is used with WithColumnCount.
+	// Extra[0] is for v6, Extra[1] is for v7, and so on.
+	Extra [][]string
+}
+
+// genData returns the filtering conditions ordered by the table columns, starts with p_type.
+func (filter Filter) genData() []Condition {
+	values := make([][]string, 0, defaultColumnCount+1+len(filter.Extra))
+	values = append(values, filter.PType, filter.V0, filter.V1, filter.V2, filter.V3, filter.V4, filter.V5)
+	values = append(values, filter.Extra...)
+
+	data := make([]Condition, len(values))
+	for idx, value := range values {
+		data[idx] = Equal(value...)
+	}
+
+	return data
+}
+
+// Operator  the matching operator of a Condition.
+type Operator int
+
+const (
+	// OpEqual  the value equals one of the values.
+	OpEqual Operator = iota
+	// OpPrefix  the value starts with one of the values.
+	OpPrefix
+	// OpLike  the value matches one of the SQL LIKE patterns, the wildcards are % and _,
+	// they are matched literally after the escape character '!'.
+	OpLike
+	// OpGlob  the value matches one of the glob patterns, the wildcards are * and ?.
+	OpGlob
+	// OpNotEqual  the value equals none of the values.
+	OpNotEqual
+)
+
+// Condition  the filtering rule of a column in FilterEx, the empty Values are ignored.
+// The patterns are matched by LIKE, so the case sensitivity depends on the database,
+// e.g. SQLite and the default collations of MySQL and SQL Server are case-insensitive.
+type Condition struct {
+	Op     Operator
+	Values []string
+}
+
+// Equal returns the Condition which matches one of the values.
+func Equal(values ...string) Condition {
+	return Condition{Op: OpEqual, Values: values}
+}
+
+// NotEqual returns the Condition which matches the values except the values, e.g. NotEqual("g2") for every ptype except g2.
+func NotEqual(values ...string) Condition {
+	return Condition{Op: OpNotEqual, Values: values}
+}
+
+// Prefix returns the Condition which matches the values starting with one of the prefixes.
+func Prefix(prefixes ...string) Condition {
+	return Condition{Op: OpPrefix, Values: prefixes}
 }
 
-type filterData struct {
-	fieldName string
-	arg       []string
+// Like returns the Condition which matches one of the SQL LIKE patterns.
+func Like(patterns ...string) Condition {
+	return Condition{Op: OpLike, Values: patterns}
 }
 
-func (filter Filter) genData() [maxParameterCount]filterData {
-	return [maxParameterCount]filterData{
-		{"p_type", filter.PType},
-		{"v0", filter.V0},
-		{"v1", filter.V1},
-		{"v2", filter.V2},
-		{"v3", filter.V3},
-		{"v4", filter.V4},
-		{"v5", filter.V5},
+// Glob returns the Condition which matches one of the glob patterns.
+func Glob(patterns ...string) Condition {
+	return Condition{Op: OpGlob, Values: patterns}
+}
+
+// FilterEx  the Filter with the operators per column, e.g. FilterEx{V1: Prefix("/orgs/42/")}.
+// The empty conditions are ignored, but all others must match the FilterEx.
+type FilterEx struct {
+	PType Condition
+	V0    Condition
+	V1    Condition
+	V2    Condition
+	V3    Condition
+	V4    Condition
+	V5    Condition
+
+	// Extra  the conditions for the columns after v5, it is used with WithColumnCount.
+	// Extra[0] is for v6, Extra[1] is for v7, and so on.
+	Extra []Condition
+}
+
+// genData returns the filtering conditions ordered by the table columns, starts with p_type.
+func (filter FilterEx) genData() []Condition {
+	data := make([]Condition, 0, defaultColumnCount+1+len(filter.Extra))
+	data = append(data, filter.PType, filter.V0, filter.V1, filter.V2, filter.V3, filter.V4, filter.V5)
+
+	return append(data, filter.Extra...)
+}
+
+// genFilters returns the filtering conditions of the filters of LoadFilteredPolicy,
+// the rules matched by any of the filters are loaded.
+func genFilters(filterPtr interface{}) ([][]Condition, error) {
+	switch filter := filterPtr.(type) {
+	case *Filter, Filter, *FilterEx, FilterEx:
+		filterData, err := genFilter(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		return [][]Condition{filterData}, nil
+	case []*Filter, []Filter, []*FilterEx, []FilterEx:
+		list := reflect.ValueOf(filter)
+		filters := make([][]Condition, list.Len())
+
+		for idx := range filters {
+			filterData, err := genFilter(list.Index(idx).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("filter %d: %w", idx, err)
+			}
+
+			filters[idx] = filterData
+		}
+
+		return checkFilters(filters)
+	case string:
+		return parseFilters(filter)
+	default:
+		return nil, &InvalidFilterError{Type: fmt.Sprintf("%T", filterPtr)}
+	}
+}
+
+// genOptionalFilters returns the filtering conditions of genFilters, there is no condition if filterPtr is nil.
+func genOptionalFilters(filterPtr interface{}) ([][]Condition, error) {
+	if filterPtr == nil {
+		return nil, nil
 	}
+
+	return genFilters(filterPtr)
+}
+
+// genFilter returns the filtering conditions of a Filter or FilterEx, or the pointer of them.
+func genFilter(filter interface{}) ([]Condition, error) {
+	switch filter := filter.(type) {
+	case *Filter:
+		if filter == nil {
+			return nil, errors.New("filter is nil")
+		}
+
+		return filter.genData(), nil
+	case Filter:
+		return filter.genData(), nil
+	case *FilterEx:
+		if filter == nil {
+			return nil, errors.New("filter is nil")
+		}
+
+		return filter.genData(), nil
+	case FilterEx:
+		return filter.genData(), nil
+	default:
+		return nil, &InvalidFilterError{Type: fmt.Sprintf("%T", filter)}
+	}
+}
+
+// parseFilters returns the filtering conditions of the text filters, e.g. "p, , data1".
+// Each line is a filter of the comma separated values, starts with p_type, the empty values match all the values.
+// The empty lines and the lines start with "#" are skipped.
+func parseFilters(text string) ([][]Condition, error) {
+	var filters [][]Condition
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, ",")
+		filterData := make([]Condition, len(fields))
+
+		for idx, field := range fields {
+			if field = strings.TrimSpace(field); field != "" {
+				filterData[idx] = Equal(field)
+			}
+		}
+
+		filters = append(filters, filterData)
+	}
+
+	return checkFilters(filters)
+}
+
+// checkFilters returns an error if the filter list is empty, it is not regarded as no filter which loads all the rules.
+func checkFilters(filters [][]Condition) ([][]Condition, error) {
+	if len(filters) == 0 {
+		return nil, errors.New("the filter list is empty")
+	}
+
+	return filters, nil
 }
@@ -0,0 +1,93 @@
+// Copyright 2026 by Blank-Xu. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqladapter
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// nolint: funlen,paralleltest
+func TestGenFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  interface{}
+		want    [][]Condition
+		wantErr string
+	}{
+		{
+			name:   "01 filter value",
+			filter: Filter{PType: []string{"p"}, V1: []string{"data1"}},
+			want:   [][]Condition{Filter{PType: []string{"p"}, V1: []string{"data1"}}.genData()},
+		},
+		{
+			name:   "02 filter list",
+			filter: []FilterEx{{V0: Prefix("a")}, {V1: NotEqual("b")}},
+			want:   [][]Condition{FilterEx{V0: Prefix("a")}.genData(), FilterEx{V1: NotEqual("b")}.genData()},
+		},
+		{
+			name:   "03 text",
+			filter: "p, , data1\n\n# the grouping rules\n g,alice ",
+			want: [][]Condition{
+				{Equal("p"), {}, Equal("data1")},
+				{Equal("g"), Equal("alice")},
+			},
+		},
+		{
+			name:    "04 empty text",
+			filter:  " \n",
+			wantErr: "the filter list is empty",
+		},
+		{
+			name:    "05 nil filter in list",
+			filter:  []*Filter{nil},
+			wantErr: "filter 0: filter is nil",
+		},
+		{
+			name:    "06 invalid type",
+			filter:  map[string]string{"p_type": "p"},
+			wantErr: "sqladapter: invalid filter type: map[string]string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := genFilters(tt.filter)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("test case[%s] failed, err: %v, want: %s", tt.name, err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("test case[%s] failed, err: %v", tt.name, err)
+			}
+
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("test case[%s] failed, got: %v, want: %v", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	_, err := genFilters(42)
+
+	var filterErr *InvalidFilterError
+	if !errors.As(err, &filterErr) || filterErr.Type != "int" {
+		t.Errorf("want *InvalidFilterError of int, got: %v", err)
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright 2026 by Blank-Xu. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqladapter
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Option  the optional configuration for the Adapter constructors.
+type Option func(*options)
+
+type options struct {
+	schema string
+
+	columnCount int
+
+	// batchSize  the maximum number of rows in a multi-row insert statement.
+	batchSize int
+
+	// pageSize  the number of rows in a page of the policy loads, 0 loads all the rows by one query.
+	pageSize int
+	snapshot bool
+
+	// stmtCacheSize  the number of the cached prepared statements, 0 disables the cache.
+	stmtCacheSize int
+
+	// columnMapping  the default column name to the custom column name.
+	columnMapping map[string]string
+
+	uniqueIndex      bool
+	ignoreDuplicates bool
+
+	timestamps bool
+	softDelete bool
+
+	autoMigrate bool
+	withoutDDL  bool
+
+	copyFrom bool
+	diffSave bool
+
+	// readDB  the optional executor of the policy loads, e.g. the read replica pool.
+	readDB DBTX
+}
+
+func newOptions(opts []Option) (options, error) {
+	o := options{
+		columnCount:   defaultColumnCount,
+		batchSize:     defaultBatchSize,
+		stmtCacheSize: defaultStmtCacheSize,
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	if o.ignoreDuplicates {
+		o.uniqueIndex = true
+	}
+
+	if o.withoutDDL && o.autoMigrate {
+		return o, errors.New("WithAutoMigrate can not be used with WithoutDDL")
+	}
+
+	if o.columnCount < 1 || o.columnCount > maxColumnCount {
+		return o, fmt.Errorf("invalid column count: %d, it must be between 1 and %d", o.columnCount, maxColumnCount)
+	}
+
+	if o.batchSize < 1 {
+		return o, fmt.Errorf("invalid batch size: %d, it must be positive", o.batchSize)
+	}
+
+	if o.stmtCacheSize < 0 {
+		return o, fmt.Errorf("invalid statement cache size: %d, it must not be negative", o.stmtCacheSize)
+	}
+
+	if o.pageSize < 0 {
+		return o, fmt.Errorf("invalid page size: %d, it must not be negative", o.pageSize)
+	}
+
+	if o.snapshot && o.pageSize == 0 {
+		return o, errors.New("WithSnapshot requires WithPageSize")
+	}
+
+	if err := o.validateColumnMapping(); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// validateColumnMapping check the mapping keys are the default column names,
+// and the custom column names are not empty or duplicated.
+func (o options) validateColumnMapping() error {
+	if len(o.columnMapping) == 0 {
+		return nil
+	}
+
+	defaultNames := o.defaultColumnNames()
+	defaultNames = append(defaultNames, defaultColumnID)
+
+	if o.timestamps {
+		defaultNames = append(defaultNames, defaultColumnCreatedAt, defaultColumnUpdatedAt)
+	}
+
+	if o.softDelete {
+		defaultNames = append(defaultNames, defaultColumnDeletedAt)
+	}
+
+	known := make(map[string]struct{}, len(defaultNames))
+	for _, name := range defaultNames {
+		known[name] = struct{}{}
+	}
+
+	for key, name := range o.columnMapping {
+		if _, ok := known[key]; !ok {
+			return fmt.Errorf("invalid column mapping: unknown column %q", key)
+		}
+
+		if err := validateIdentifier("column name", name); err != nil {
+			return fmt.Errorf("invalid column mapping: %w", err)
+		}
+	}
+
+	used := make(map[string]struct{}, len(defaultNames))
+	for _, name := range defaultNames {
+		name = o.columnName(name)
+
+		if _, ok := used[name]; ok {
+			return fmt.Errorf("invalid column mapping: duplicate column name %q", name)
+		}
+
+		used[name] = struct{}{}
+	}
+
+	return nil
+}
+
+// defaultColumnNames returns p_type, v0, v1, ... by the column count.
+func (o options) defaultColumnNames() []string {
+	names := make([]string, 0, o.columnCount+2)
+	names = append(names, defaultColumnPType)
+
+	for idx := 0; idx < o.columnCount; idx++ {
+		names = append(names, "v"+strconv.Itoa(idx))
+	}
+
+	return names
+}
+
+// columnName returns the custom column name of the default column name.
+func (o options) columnName(name string) string {
+	if mapped, ok := o.columnMapping[name]; ok {
+		return mapped
+	}
+
+	return name
+}
+
+// columnNames returns the rule column names of the table, starts with p_type.
+func (o options) columnNames() []string {
+	names := o.defaultColumnNames()
+
+	for idx, name := range names {
+		names[idx] = o.columnName(name)
+	}
+
+	return names
+}
+
+// WithColumnCount  set the number of policy value columns (v0, v1, ...) in the table.
+// The default is 6 (v0 to v5), policy rules with more fields than count will be rejected.
+func WithColumnCount(count int) Option {
+	return func(o *options) {
+		o.columnCount = count
+	}
+}
+
+// WithBatchSize  set the maximum number of rows in a multi-row insert statement of AddPolicies and SavePolicy,
+// the default is 1000. The statements have fewer rows if the bind parameters exceed the limit of the database.
+// The size 1 inserts the rows one by one.
+func WithBatchSize(size int) Option {
+	return func(o *options) {
+		o.batchSize = size
+	}
+}
+
+// WithStmtCacheSize  set the number of the prepared statements cached by the Adapter, the default is 64.
+// The fixed queries and the filter shapes are prepared once, and the least recently used statements are closed.
+// The size 0 disables the cache, e.g. for the connection poolers which do not support the prepared statements.
+// The cached statements are closed by Adapter.Close.
+func WithStmtCacheSize(size int) Option {
+	return func(o *options) {
+		o.stmtCacheSize = size
+	}
+}
+
+// WithPageSize  load the policy rules in pages of size rows ordered by the id column,
+// each page starts after the last id of the previous page, so the large tables are read by the short queries.
+// The table needs the id column, it is added by the migration, see WithAutoMigrate.
+// The size 0 loads all the rules by one query, it is the default.
+func WithPageSize(size int) Option {
+	return func(o *options) {
+		o.pageSize = size
+	}
+}
+
+// WithSnapshot  read all the pages of WithPageSize in one transaction,
+// so the loaded rules are a consistent snapshot, e.g. the repeatable read transaction of PostgreSQL and MySQL.
+// SQL Server requires ALLOW_SNAPSHOT_ISOLATION ON of the database.
+// The read executors which can not begin a transaction, e.g. the transaction of WithTx, read the pages by themselves.
+func WithSnapshot() Option {
+	return func(o *options) {
+		o.snapshot = true
+	}
+}
+
+// WithSchema  set the schema of the table, e.g. "analytics" in PostgreSQL, "dbo" in SQL Server,
+// the database name in MySQL, or the attached database name in SQLite.
+// The table name "schema.table" has the same effect.
+func WithSchema(schema string) Option {
+	return func(o *options) {
+		o.schema = schema
+	}
+}
+
+// WithColumnMapping  use the custom column names to share the table with other adapters.
+// The mapping keys are the default column names: "p_type", "v0", "v1", ..., "id",
+// "created_at", "updated_at" with WithTimestamps, and "deleted_at" with WithSoftDelete,
+// the unmapped columns use the default names.
+// E.g. the table created by gorm-adapter: WithColumnMapping(map[string]string{"p_type": "ptype"}).
+func WithColumnMapping(mapping map[string]string) Option {
+	return func(o *options) {
+		o.columnMapping = make(map[string]string, len(mapping))
+		for key, name := range mapping {
+			o.columnMapping[key] = name
+		}
+	}
+}
+
+// WithUniqueIndex  create the table with an "id" primary key and a unique index on all the rule columns,
+// MySQL and SQL Server index the hash of the columns because of the index key size limits.
+// Adding a rule that already exists returns a *DuplicateRuleError.
+// The existing table gets the index by the migration, the duplicate rules are removed, see Adapter.Migrate.
+func WithUniqueIndex() Option {
+	return func(o *options) {
+		o.uniqueIndex = true
+	}
+}
+
+// WithIgnoreDuplicates  skip the rules that already exist when adding rules, instead of returning a *DuplicateRuleError.
+// It implies WithUniqueIndex.
+func WithIgnoreDuplicates() Option {
+	return func(o *options) {
+		o.ignoreDuplicates = true
+	}
+}
+
+// WithTimestamps  add the "created_at" and "updated_at" columns to the table,
+// they are set by the database clock when the rules are inserted or updated,
+// and can be read by Adapter.LoadAuditedRules.
+// The columns are added to an existing table by Adapter.Migrate.
+func WithTimestamps() Option {
+	return func(o *options) {
+		o.timestamps = true
+	}
+}
+
+// WithSoftDelete  add the "deleted_at" column to the table, the removed rules are marked as deleted
+// by the database clock instead of being deleted, and they are not loaded anymore.
+// With WithUniqueIndex, the unique index only applies to the rules which are not deleted.
+// The deleted rules can be deleted physically by Adapter.PurgeDeleted.
+// The column is added to an existing table by Adapter.Migrate.
+func WithSoftDelete() Option {
+	return func(o *options) {
+		o.softDelete = true
+	}
+}
+
+// WithAutoMigrate  apply the pending schema migrations when the Adapter is created, see Adapter.Migrate.
+// Without it, the constructors return *PendingMigrationsError if the existing table has pending migrations.
+func WithAutoMigrate() Option {
+	return func(o *options) {
+		o.autoMigrate = true
+	}
+}
+
+// WithoutDDL  the Adapter never executes DDL statements, it is for the database users without DDL privileges.
+// If the table does not exist, the constructors return ErrTableNotExist,
+// the table can be created by CreateTable or the statements from CreateTableSQL, and upgraded by MigrateTable.
+func WithoutDDL() Option {
+	return func(o *options) {
+		o.withoutDDL = true
+	}
+}
+
+// WithCopyFrom  SavePolicy loads the rules by COPY FROM STDIN of PostgreSQL in the transaction of the delete.
+// lib/pq copies by the statement of database/sql, pgx copies by the CopyFrom of pgconn on the same connection by sql.Conn.Raw,
+// the transactions of WithTx insert the rules for pgx. With the other drivers the rules are inserted by the insert statements.
+// It does not work with WithIgnoreDuplicates.
+func WithCopyFrom() Option {
+	return func(o *options) {
+		o.copyFrom = true
+	}
+}
+
+// WithDiffSave  SavePolicy only inserts the new rules and deletes the removed rules in one transaction,
+// instead of deleting all the rules and inserting them again,
+// so the unchanged rules keep their ids and timestamps, see Adapter.SavePolicyDiff.
+func WithDiffSave() Option {
+	return func(o *options) {
+		o.diffSave = true
+	}
+}
+
+// WithReadDB  load the policy rules from db, e.g. the pool of the read replicas,
+// the writes, the migrations and the reads before writes still use the primary db of the constructors.
+// The loads right after the writes can read from the primary db by the context of ReadFromPrimary.
+func WithReadDB(db DBTX) Option {
+	return func(o *options) {
+		o.readDB = db
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2026 by Blank-Xu. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqladapter
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// stmtCache  the least recently used statements prepared by db, see WithStmtCacheSize.
+// The cached statements are used by the transactions began by db with StmtContext,
+// the other executors, e.g. the transactions of the caller, prepare the statements by themselves.
+type stmtCache struct {
+	db   *sql.DB
+	size int
+
+	mu sync.Mutex
+	// lru  the cachedStmt list, the front is the most recently used.
+	lru     *list.List
+	entries map[string]*list.Element
+	closed  bool
+}
+
+// cachedStmt  the statement is closed after it is evicted and released by all the users.
+type cachedStmt struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
+}
+
+// newStmtCache returns nil if size is 0, the nil cache does not cache the statements.
+func newStmtCache(db *sql.DB, size int) *stmtCache {
+	if db == nil || size <= 0 {
+		return nil
+	}
+
+	return &stmtCache{
+		db:      db,
+		size:    size,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// stmt returns the cached statement of query for exec, and the func to release it after use.
+// ok is false if the statement is not cached, e.g. exec is not the db or a transaction began by the db.
+func (c *stmtCache) stmt(ctx context.Context, exec DBTX, query string) (stmt *sql.Stmt, release func() error, ok bool, err error) {
+	if c == nil {
+		return nil, nil, false, nil
+	}
+
+	var tx *sql.Tx
+
+	switch e := exec.(type) {
+	case *sql.DB:
+		if e != c.db {
+			return nil, nil, false, nil
+		}
+	case daoTx:
+		if e.tx == nil {
+			return c.stmt(ctx, e.DBTX, query)
+		}
+
+		if db, ok := e.beginner.(*sql.DB); !ok || db != c.db {
+			return nil, nil, false, nil
+		}
+
+		tx = e.tx
+	default:
+		return nil, nil, false, nil
+	}
+
+	if tx == nil {
+		entry, err := c.acquire(ctx, query)
+		if err != nil || entry == nil {
+			return nil, nil, false, err
+		}
+
+		return entry.stmt, func() error { c.release(entry); return nil }, true, nil
+	}
+
+	// the transaction holds a connection, db may wait for another connection to prepare the statement,
+	// e.g. the pool of one connection waits for itself. So the statement which is not cached is executed by tx.
+	entry := c.lookup(query)
+	if entry == nil {
+		return nil, nil, false, nil
+	}
+
+	txStmt := tx.StmtContext(ctx, entry.stmt)
+
+	return txStmt, func() error {
+		err := txStmt.Close()
+		c.release(entry)
+
+		return err
+	}, true, nil
+}
+
+// acquire returns the cached statement of query, it prepares the statement if it is not cached.
+// It returns nil if the cache is closed.
+func (c *stmtCache) acquire(ctx context.Context, query string) (*cachedStmt, error) {
+	c.mu.Lock()
+
+	if c.closed {
+		c.mu.Unlock()
+
+		return nil, nil
+	}
+
+	if entry := c.cached(query); entry != nil {
+		c.mu.Unlock()
+
+		return entry, nil
+	}
+
+	c.mu.Unlock()
+
+	// the statement is prepared without the lock, the other statements can be used meanwhile.
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		_ = stmt.Close()
+
+		return nil, nil
+	}
+
+	// another goroutine prepared the same query.
+	if entry := c.cached(query); entry != nil {
+		_ = stmt.Close()
+
+		return entry, nil
+	}
+
+	entry := &cachedStmt{query: query, stmt: stmt, refs: 1}
+	c.entries[query] = c.lru.PushFront(entry)
+
+	for c.lru.Len() > c.size {
+		_ = c.evict(c.lru.Back())
+	}
+
+	return entry, nil
+}
+
+// lookup returns the cached statement of query, it returns nil if the statement is not cached or the cache is closed.
+func (c *stmtCache) lookup(query string) *cachedStmt {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+
+	return c.cached(query)
+}
+
+// cached returns the cached statement of query and marks it in use, or nil.
+// The caller holds the lock.
+func (c *stmtCache) cached(query string) *cachedStmt {
+	elem, ok := c.entries[query]
+	if !ok {
+		return nil
+	}
+
+	entry := elem.Value.(*cachedStmt)
+	entry.refs++
+	c.lru.MoveToFront(elem)
+
+	return entry
+}
+
+// release the statement after use, the evicted statement is closed by the last user.
+func (c *stmtCache) release(entry *cachedStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.refs--
+
+	if entry.evicted && entry.refs == 0 {
+		_ = entry.stmt.Close()
+	}
+}
+
+// evict remove the statement from the cache, it is closed if it is not in use.
+// The caller holds the lock.
+func (c *stmtCache) evict(elem *list.Element) error {
+	entry := c.lru.Remove(elem).(*cachedStmt)
+	delete(c.entries, entry.query)
+
+	entry.evicted = true
+
+	if entry.refs == 0 {
+		return entry.stmt.Close()
+	}
+
+	return nil
+}
+
+// close the cached statements, the statements in use are closed after they are released.
+// The closed cache does not cache the statements. It returns the first error of the statements.
+func (c *stmtCache) close() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+
+	var result error
+
+	for c.lru.Len() != 0 {
+		if err := c.evict(c.lru.Back()); err != nil && result == nil {
+			result = err
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,221 @@
+// Copyright 2026 by Blank-Xu. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqladapter
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+)
+
+// stmtCounter  counts the prepared and closed statements of stmtConn.
+type stmtCounter struct {
+	prepared int
+	closed   int
+}
+
+type stmtConn struct {
+	counter *stmtCounter
+}
+
+func (c stmtConn) Prepare(string) (driver.Stmt, error) {
+	c.counter.prepared++
+
+	return stmtStmt(c), nil
+}
+
+func (stmtConn) Close() error {
+	return nil
+}
+
+func (c stmtConn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+
+func (stmtConn) Commit() error {
+	return nil
+}
+
+func (stmtConn) Rollback() error {
+	return nil
+}
+
+type stmtStmt struct {
+	counter *stmtCounter
+}
+
+func (s stmtStmt) Close() error {
+	s.counter.closed++
+
+	return nil
+}
+
+func (stmtStmt) NumInput() int {
+	return -1
+}
+
+func (stmtStmt) Exec([]driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (stmtStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("stmt test driver can not query")
+}
+
+type stmtConnector struct {
+	counter *stmtCounter
+}
+
+func (c stmtConnector) Connect(context.Context) (driver.Conn, error) {
+	return stmtConn(c), nil
+}
+
+func (stmtConnector) Driver() driver.Driver {
+	return testDriver{}
+}
+
+// nolint: paralleltest
+func TestStmtCache(t *testing.T) {
+	counter := &stmtCounter{}
+
+	db := sql.OpenDB(stmtConnector{counter: counter})
+	defer db.Close()
+
+	// the statements of the transactions are prepared on the same connection.
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	d := dao{db: db, stmts: newStmtCache(db, 2)}
+
+	exec := func(executor DBTX, query string) {
+		t.Helper()
+
+		if _, err := d.exec(ctx, executor, query); err != nil {
+			t.Fatalf("exec %s failed, err: %v", query, err)
+		}
+	}
+
+	want := func(prepared, closed int) {
+		t.Helper()
+
+		if counter.prepared != prepared || counter.closed != closed {
+			t.Errorf("got prepared: %d, closed: %d, want prepared: %d, closed: %d", counter.prepared, counter.closed, prepared, closed)
+		}
+	}
+
+	exec(db, "q1")
+	exec(db, "q2")
+	exec(db, "q1")
+	want(2, 0)
+
+	// q2 is the least recently used statement.
+	exec(db, "q3")
+	want(3, 1)
+
+	tx, err := d.beginTx(ctx)
+	if err != nil {
+		t.Fatal("begin tx failed, err: ", err)
+	}
+
+	exec(tx, "q1")
+	want(3, 1)
+
+	if err = tx.Commit(); err != nil {
+		t.Fatal("commit failed, err: ", err)
+	}
+
+	// the transaction of the caller does not use the cache,
+	// the test driver has no Execer, so database/sql prepares and closes the statement.
+	callerTx, err := db.Begin()
+	if err != nil {
+		t.Fatal("begin tx failed, err: ", err)
+	}
+
+	exec(callerTx, "q1")
+	want(4, 2)
+
+	if err = callerTx.Rollback(); err != nil {
+		t.Fatal("rollback failed, err: ", err)
+	}
+
+	if err = d.stmts.close(); err != nil {
+		t.Fatal("close failed, err: ", err)
+	}
+
+	want(4, 4)
+
+	// the closed cache does not cache the statements.
+	exec(db, "q1")
+	exec(db, "q1")
+	want(6, 6)
+}
+
+// nolint: paralleltest
+func TestStmtCacheTxMiss(t *testing.T) {
+	counter := &stmtCounter{}
+
+	db := sql.OpenDB(stmtConnector{counter: counter})
+	defer db.Close()
+
+	// the transaction holds the only connection, db can not prepare the statement.
+	db.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	d := dao{db: db, stmts: newStmtCache(db, 2)}
+
+	tx, err := d.beginTx(ctx)
+	if err != nil {
+		t.Fatal("begin tx failed, err: ", err)
+	}
+
+	if _, err = d.exec(ctx, tx, "q1"); err != nil {
+		t.Fatal("exec q1 failed, err: ", err)
+	}
+
+	stmt, release, err := d.prepare(ctx, tx, "q2")
+	if err != nil {
+		t.Fatal("prepare q2 failed, err: ", err)
+	}
+	if _, err = stmt.ExecContext(ctx); err != nil {
+		t.Fatal("exec q2 failed, err: ", err)
+	}
+	if err = release(); err != nil {
+		t.Fatal("release q2 failed, err: ", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		t.Fatal("commit failed, err: ", err)
+	}
+
+	// the statements are prepared on the connection of the transaction, and not cached.
+	if counter.prepared != 2 || counter.closed != 2 || d.stmts.lru.Len() != 0 {
+		t.Errorf("got prepared: %d, closed: %d, cached: %d, want prepared: 2, closed: 2, cached: 0",
+			counter.prepared, counter.closed, d.stmts.lru.Len())
+	}
+
+	// the statement is cached after the transaction.
+	if _, err = d.exec(ctx, db, "q1"); err != nil {
+		t.Fatal("exec q1 failed, err: ", err)
+	}
+
+	if counter.prepared != 3 || d.stmts.lru.Len() != 1 {
+		t.Errorf("got prepared: %d, cached: %d, want prepared: 3, cached: 1", counter.prepared, d.stmts.lru.Len())
+	}
+}
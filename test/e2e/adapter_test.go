@@ -15,12 +15,18 @@
 package sqladaptertest
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/Blank-Xu/sql-adapter"
 	"github.com/casbin/casbin/v3"
+	"github.com/casbin/casbin/v3/model"
 )
 
 const (
@@ -41,6 +47,23 @@ func TestAdapter(t *testing.T) {
 		testUpdatePolicy(t, db, driverName, "sqladapter_test_update_policy")
 		testUpdatePolicies(t, db, driverName, "sqladapter_test_update_policies")
 		testUpdateFilteredPolicies(t, db, driverName, "sqladapter_test_update_filtered_policies")
+		testColumnCount(t, db, driverName, "sqladapter_test_column_count")
+		testUniqueIndex(t, db, driverName, "sqladapter_test_unique_index")
+		testMigrate(t, db, driverName, "sqladapter_test_migrate")
+		testColumnMapping(t, db, driverName, "sqladapter_test_column_mapping")
+		testSchema(t, db, driverName, "SQLAdapter_Test_Schema")
+		testWithoutDDL(t, db, driverName, "sqladapter_test_without_ddl")
+		testTimestamps(t, db, driverName, "sqladapter_test_timestamps")
+		testSoftDelete(t, db, driverName, "sqladapter_test_soft_delete")
+		testDetectDialect(t, db, driverName, "sqladapter_test_detect_dialect")
+		testWithTx(t, db, driverName, "sqladapter_test_with_tx")
+		testReadDB(t, db, driverName, "sqladapter_test_read_db")
+		testBatchInsert(t, db, driverName, "sqladapter_test_batch_insert")
+		testDiffSave(t, db, driverName, "sqladapter_test_diff_save")
+		testForEachRule(t, db, driverName, "sqladapter_test_for_each_rule")
+		testPageSize(t, db, driverName, "sqladapter_test_page_size")
+		testFilterEx(t, db, driverName, "sqladapter_test_filter_ex")
+		testFilteredSave(t, db, driverName, "sqladapter_test_filtered_save")
 
 		t.Logf("adapter test for [%s] finished", driverName)
 	}
@@ -281,6 +304,45 @@ func testFilteredPolicy(t *testing.T, db *sql.DB, driverName, tableName string)
 			validatePolicies(t, policies, tt.expectPolicy)
 		})
 	}
+
+	t.Run("FilteredPolicy_06_filter_shapes", func(t *testing.T) {
+		filters := []interface{}{
+			Filter{PType: []string{"p"}, V1: []string{"data1"}},
+			[]Filter{{PType: []string{"p"}, V1: []string{"data1"}}},
+			"p, , data1",
+		}
+		for _, filter := range filters {
+			if err = e.LoadFilteredPolicy(filter); err != nil {
+				t.Errorf("%T LoadFilteredPolicy test failed, err: %v", filter, err)
+			}
+
+			policies, err := e.GetPolicy()
+			validateNilError(t, err)
+			validatePolicies(t, policies, [][]string{{"alice", "data1", "read"}, {"bob", "data1", "write"}})
+		}
+
+		var filterErr *InvalidFilterError
+		if err = e.LoadFilteredPolicy(map[string]string{"p_type": "p"}); !errors.As(err, &filterErr) {
+			t.Errorf("want *InvalidFilterError, got: %v", err)
+		}
+	})
+
+	t.Run("FilteredPolicy_07_nil_filter_ctx", func(t *testing.T) {
+		// the nil filter loads all the rules with the context.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if err = a.LoadFilteredPolicyCtx(ctx, e.GetModel(), nil); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s test failed, want context.Canceled, got: %v", "LoadFilteredPolicyCtx", err)
+		}
+
+		if err = a.LoadFilteredPolicyCtx(context.Background(), e.GetModel(), nil); err != nil {
+			t.Errorf("%s test failed, err: %v", "LoadFilteredPolicyCtx", err)
+		}
+		if a.IsFiltered() {
+			t.Errorf("%s test failed, the adapter is filtered", "LoadFilteredPolicyCtx")
+		}
+	})
 }
 
 func testUpdatePolicy(t *testing.T, db *sql.DB, driverName, tableName string) {
@@ -349,6 +411,1058 @@ func testUpdateFilteredPolicies(t *testing.T, db *sql.DB, driverName, tableName
 	})
 }
 
+func testColumnCount(t *testing.T, db *sql.DB, driverName, tableName string) {
+	const modelText = `
+[request_definition]
+r = sub, dom, obj, act, env, app, ip, tag
+
+[policy_definition]
+p = sub, dom, obj, act, env, app, ip, tag
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = r.sub == p.sub && r.dom == p.dom && r.obj == p.obj && r.act == p.act
+`
+
+	t.Run("ColumnCount", func(t *testing.T) {
+		m, err := model.NewModelFromString(modelText)
+		if err != nil {
+			t.Fatal("casbin NewModelFromString failed, err: ", err)
+		}
+
+		a, err := NewAdapter(db, driverName, tableName, WithColumnCount(8))
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		e, _ := casbin.NewEnforcer(m, a)
+		e.ClearPolicy()
+		if err = e.SavePolicy(); err != nil {
+			t.Fatalf("%s test failed, err: %v", "SavePolicy", err)
+		}
+
+		rule := []string{"alice", "domain1", "data1", "read", "prod", "app1", "127.0.0.1", "tag1"}
+		if _, err = e.AddPolicy(rule); err != nil {
+			t.Errorf("%s test failed, err: %v", "AddPolicy", err)
+		}
+		if err = e.LoadPolicy(); err != nil {
+			t.Errorf("%s test failed, err: %v", "LoadPolicy", err)
+		}
+		policies, err := e.GetPolicy()
+		validateNilError(t, err)
+		validatePolicies(t, policies, [][]string{rule})
+
+		if err = e.LoadFilteredPolicy(&Filter{Extra: [][]string{{"127.0.0.1"}, {"tag1"}}}); err != nil {
+			t.Errorf("%s test failed, err: %v", "LoadFilteredPolicy", err)
+		}
+		policies, err = e.GetPolicy()
+		validateNilError(t, err)
+		validatePolicies(t, policies, [][]string{rule})
+
+		if err = a.AddPolicy("p", "p", append(rule, "too_long")); err == nil {
+			t.Errorf("%s test failed, the rule longer than the column count should be rejected", "AddPolicy")
+		}
+	})
+}
+
+func testUniqueIndex(t *testing.T, db *sql.DB, driverName, tableName string) {
+	t.Run("UniqueIndex_01_DuplicateRuleError", func(t *testing.T) {
+		a, err := NewAdapter(db, driverName, tableName, WithUniqueIndex())
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		e, _ := casbin.NewEnforcer(testRbacModelFile, testRbacPolicyFile)
+		if err = a.SavePolicy(e.GetModel()); err != nil {
+			t.Fatalf("%s test failed, err: %v", "SavePolicy", err)
+		}
+
+		err = a.AddPolicy("p", "p", []string{"alice", "data1", "read"})
+
+		var duplicateErr *DuplicateRuleError
+		if !errors.As(err, &duplicateErr) {
+			t.Errorf("%s test failed, want *DuplicateRuleError, got: %v", "AddPolicy", err)
+		}
+
+		err = a.AddPolicies("p", "p", [][]string{{"alice", "data3", "read"}, {"alice", "data1", "read"}})
+		if !errors.As(err, &duplicateErr) {
+			t.Errorf("%s test failed, want *DuplicateRuleError, got: %v", "AddPolicies", err)
+		}
+	})
+
+	t.Run("UniqueIndex_02_IgnoreDuplicates", func(t *testing.T) {
+		a, err := NewAdapter(db, driverName, tableName, WithIgnoreDuplicates())
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		e, _ := casbin.NewEnforcer(testRbacModelFile, a)
+		if err = a.AddPolicy("p", "p", []string{"alice", "data1", "read"}); err != nil {
+			t.Errorf("%s test failed, err: %v", "AddPolicy", err)
+		}
+		if err = a.AddPolicies("p", "p", [][]string{{"alice", "data1", "write"}, {"bob", "data2", "write"}}); err != nil {
+			t.Errorf("%s test failed, err: %v", "AddPolicies", err)
+		}
+		if err = e.LoadPolicy(); err != nil {
+			t.Errorf("%s test failed, err: %v", "LoadPolicy", err)
+		}
+		policies, err := e.GetPolicy()
+		validateNilError(t, err)
+		validatePolicies(t, policies, append(testDefaultPolicy, []string{"alice", "data1", "write"}))
+	})
+}
+
+func testMigrate(t *testing.T, db *sql.DB, driverName, tableName string) {
+	t.Run("Migrate", func(t *testing.T) {
+		for _, name := range []string{tableName, tableName + "_migrations"} {
+			if _, err := db.Exec("DROP TABLE IF EXISTS " + name); err != nil {
+				t.Fatal("drop table failed, err: ", err)
+			}
+		}
+
+		// all the migrations are pending before the table is created.
+		pending, err := PendingMigrations(context.Background(), db, driverName, tableName, WithUniqueIndex())
+		validateNilError(t, err)
+		if len(pending) != 2 || pending[0].Version != 1 || pending[1].Version != 2 {
+			t.Fatalf("%s test failed, pending: %v", "PendingMigrations", pending)
+		}
+
+		// the table is created without the unique index.
+		a, err := NewAdapter(db, driverName, tableName)
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+		for i := 0; i < 2; i++ {
+			if err = a.AddPolicies("p", "p", testDefaultPolicy); err != nil {
+				t.Fatalf("%s test failed, err: %v", "AddPolicies", err)
+			}
+		}
+
+		// the writes fail on the table without the unique index.
+		_, err = NewAdapter(db, driverName, tableName, WithUniqueIndex())
+
+		var pendingErr *PendingMigrationsError
+		if !errors.As(err, &pendingErr) {
+			t.Fatalf("%s test failed, want *PendingMigrationsError, got: %v", "NewAdapter", err)
+		}
+		if len(pendingErr.Migrations) != 1 || pendingErr.Migrations[0].Version != 2 {
+			t.Fatalf("%s test failed, pending: %v", "NewAdapter", pendingErr.Migrations)
+		}
+
+		pending, err = PendingMigrations(context.Background(), db, driverName, tableName, WithUniqueIndex())
+		validateNilError(t, err)
+		if len(pending) != 1 || pending[0].Version != 2 {
+			t.Fatalf("%s test failed, pending: %v", "PendingMigrations", pending)
+		}
+
+		if err = MigrateTable(context.Background(), db, driverName, tableName, WithUniqueIndex()); err != nil {
+			t.Fatalf("%s test failed, err: %v", "MigrateTable", err)
+		}
+
+		a, err = NewAdapter(db, driverName, tableName, WithUniqueIndex())
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		pending, err = PendingMigrations(context.Background(), db, driverName, tableName, WithUniqueIndex())
+		validateNilError(t, err)
+		if len(pending) != 0 {
+			t.Errorf("%s test failed, pending: %v", "PendingMigrations", pending)
+		}
+
+		e, _ := casbin.NewEnforcer(testRbacModelFile, a)
+		policies, err := e.GetPolicy()
+		validateNilError(t, err)
+		validatePolicies(t, policies, testDefaultPolicy)
+
+		var duplicateErr *DuplicateRuleError
+		if err = a.AddPolicy("p", "p", testDefaultPolicy[0]); !errors.As(err, &duplicateErr) {
+			t.Errorf("%s test failed, want *DuplicateRuleError, got: %v", "AddPolicy", err)
+		}
+
+		// the versions of the dropped table are replaced by the versions of the new table.
+		if _, err = db.Exec("DROP TABLE " + tableName); err != nil {
+			t.Fatal("drop table failed, err: ", err)
+		}
+
+		a, err = NewAdapter(db, driverName, tableName)
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		a, err = NewAdapter(db, driverName, tableName, WithUniqueIndex(), WithAutoMigrate())
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		if err = a.AddPolicy("p", "p", testDefaultPolicy[0]); err != nil {
+			t.Errorf("%s test failed, err: %v", "AddPolicy", err)
+		}
+		if err = a.AddPolicy("p", "p", testDefaultPolicy[0]); !errors.As(err, &duplicateErr) {
+			t.Errorf("%s test failed, want *DuplicateRuleError, got: %v", "AddPolicy", err)
+		}
+	})
+}
+
+func testColumnMapping(t *testing.T, db *sql.DB, driverName, tableName string) {
+	// the table created by gorm-adapter.
+	idColumns := map[string]string{
+		"sqlite":    "id INTEGER PRIMARY KEY AUTOINCREMENT",
+		"mysql":     "id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY",
+		"postgres":  "id BIGSERIAL PRIMARY KEY",
+		"sqlserver": "id BIGINT IDENTITY(1,1) PRIMARY KEY",
+	}
+
+	t.Run("ColumnMapping", func(t *testing.T) {
+		if _, err := db.Exec("DROP TABLE IF EXISTS " + tableName); err != nil {
+			t.Fatal("drop table failed, err: ", err)
+		}
+		_, err := db.Exec("CREATE TABLE " + tableName + "(" + idColumns[driverName] +
+			", ptype VARCHAR(100), v0 VARCHAR(100), v1 VARCHAR(100), v2 VARCHAR(100), v3 VARCHAR(100), v4 VARCHAR(100), v5 VARCHAR(100))")
+		if err != nil {
+			t.Fatal("create table failed, err: ", err)
+		}
+		if _, err = db.Exec("INSERT INTO " + tableName + " (ptype,v0,v1,v2) VALUES ('p','carol','data3','read')"); err != nil {
+			t.Fatal("insert failed, err: ", err)
+		}
+
+		a, err := NewAdapter(db, driverName, tableName, WithColumnMapping(map[string]string{"p_type": "ptype"}))
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		e, _ := casbin.NewEnforcer(testRbacModelFile, a)
+		if _, err = e.AddPolicy("alice", "data1", "read"); err != nil {
+			t.Errorf("%s test failed, err: %v", "AddPolicy", err)
+		}
+		if err = e.LoadPolicy(); err != nil {
+			t.Errorf("%s test failed, err: %v", "LoadPolicy", err)
+		}
+		policies, err := e.GetPolicy()
+		validateNilError(t, err)
+		validatePolicies(t, policies, [][]string{{"carol", "data3", "read"}, {"alice", "data1", "read"}})
+
+		if _, err = e.RemoveFilteredPolicy(0, "carol"); err != nil {
+			t.Errorf("%s test failed, err: %v", "RemoveFilteredPolicy", err)
+		}
+		if err = e.LoadPolicy(); err != nil {
+			t.Errorf("%s test failed, err: %v", "LoadPolicy", err)
+		}
+		policies, err = e.GetPolicy()
+		validateNilError(t, err)
+		validatePolicies(t, policies, [][]string{{"alice", "data1", "read"}})
+	})
+}
+
+func testSchema(t *testing.T, db *sql.DB, driverName, tableName string) {
+	schemas := map[string]string{
+		"sqlite":    "main",
+		"mysql":     "sqladapter_test",
+		"postgres":  "public",
+		"sqlserver": "dbo",
+	}
+
+	t.Run("Schema", func(t *testing.T) {
+		initPolicy(t, db, driverName, schemas[driverName]+"."+tableName)
+
+		a, err := NewAdapter(db, driverName, tableName, WithSchema(schemas[driverName]))
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		e, _ := casbin.NewEnforcer(testRbacModelFile, a)
+		if _, err = e.AddPolicy("alice", "data1", "write"); err != nil {
+			t.Errorf("%s test failed, err: %v", "AddPolicy", err)
+		}
+		if err = e.LoadPolicy(); err != nil {
+			t.Errorf("%s test failed, err: %v", "LoadPolicy", err)
+		}
+		policies, err := e.GetPolicy()
+		validateNilError(t, err)
+		validatePolicies(t, policies, append(testDefaultPolicy, []string{"alice", "data1", "write"}))
+	})
+}
+
+func testWithoutDDL(t *testing.T, db *sql.DB, driverName, tableName string) {
+	t.Run("WithoutDDL", func(t *testing.T) {
+		for _, name := range []string{tableName, tableName + "_migrations"} {
+			if _, err := db.Exec("DROP TABLE IF EXISTS " + name); err != nil {
+				t.Fatal("drop table failed, err: ", err)
+			}
+		}
+
+		_, err := NewAdapter(db, driverName, tableName, WithoutDDL())
+		if !errors.Is(err, ErrTableNotExist) {
+			t.Fatalf("%s test failed, want ErrTableNotExist, got: %v", "NewAdapter", err)
+		}
+
+		if err = CreateTable(context.Background(), db, driverName, tableName); err != nil {
+			t.Fatalf("%s test failed, err: %v", "CreateTable", err)
+		}
+
+		a, err := NewAdapter(db, driverName, tableName, WithoutDDL())
+		if err != nil {
+			t.Fatalf("%s test failed, err: %v", "NewAdapter", err)
+		}
+
+		if err = a.AddPolicy("p", "p", []string{"alice", "data1", "read"}); err != nil {
+			t.Errorf("%s test failed, err: %v", "AddPolicy", err)
+		}
+
+		// PendingMigrations does not create the version table.
+		if _, err = db.Exec("DROP TABLE " + tableName + "_migrations"); err != nil {
+			t.Fatal("drop table failed, err: ", err)
+		}
+
+		pending, err := PendingMigrations(context.Background(), db, driverName, tableName, WithoutDDL())
+		validateNilError(t, err)
+		if len(pending) != 0 {
+			t.Errorf("%s test failed, pending: %v", "PendingMigrations", pending)
+		}
+
+		pending, err = PendingMigrations(context.Background(), db, driverName, tableName, WithoutDDL(), WithTimestamps())
+		validateNilError(t, err)
+		if len(pending) != 1 || pending[0].Version != 3 {
+			t.Errorf("%s test failed, pending: %v", "PendingMigrations", pending)
+		}
+
+		if rows, err := db.Query("SELECT version FROM " + tableName + "_migrations"); err == nil {
+			rows.Close()
+			t.Errorf("%s test failed, the version table is created", "PendingMigrations")
+		}
+	})
+}
+
+func testTimestamps(t *testing.T, db *sql.DB, driverName, tableName string) {
+	t.Run("Timestamps", func(t *testing.T) {
+		for _, name := range []string{tableName, tableName + "_migrations"} {
+			if _, err := db.Exec("DROP TABLE IF EXISTS " + name); err != nil {
+				t.Fatal("drop table failed, err: ", err)
+			}
+		}
+
+		// the table is created without the timestamps, the duplicate rules are merged by the migrations.
+		a, err := NewAdapter(db, driverName, tableName)
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+		for i := 0; i < 2; i++ {
+			if err = a.AddPolicies("p", "p", testDefaultPolicy); err != nil {
+				t.Fatalf("%s test failed, err: %v", "AddPolicies", err)
+			}
+		}
+
+		if _, err = a.LoadAuditedRules(context.Background(), nil); !errors.Is(err, ErrTimestampsNotEnabled) {
+			t.Errorf("%s test failed, want ErrTimestampsNotEnabled, got: %v", "LoadAuditedRules", err)
+		}
+
+		a, err = NewAdapter(db, driverName, tableName, WithUniqueIndex(), WithTimestamps(), WithAutoMigrate())
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		if err = a.AddPolicy("p", "p", []string{"alice", "data1", "write"}); err != nil {
+			t.Errorf("%s test failed, err: %v", "AddPolicy", err)
+		}
+		if err = a.UpdatePolicy("p", "p", testDefaultPolicy[0], []string{"alice", "data3", "read"}); err != nil {
+			t.Errorf("%s test failed, err: %v", "UpdatePolicy", err)
+		}
+
+		rules, err := a.LoadAuditedRules(context.Background(), nil)
+		validateNilError(t, err)
+		if len(rules) != len(testDefaultPolicy)+1 {
+			t.Fatalf("%s test failed, rules: %v", "LoadAuditedRules", rules)
+		}
+		for _, rule := range rules {
+			if rule.CreatedAt.IsZero() || rule.UpdatedAt.Before(rule.CreatedAt) {
+				t.Errorf("%s test failed, rule: %v", "LoadAuditedRules", rule)
+			}
+		}
+
+		rules, err = a.LoadAuditedRules(context.Background(), &Filter{V0: []string{"alice"}})
+		validateNilError(t, err)
+		policies := make([][]string, 0, len(rules))
+		for _, rule := range rules {
+			policies = append(policies, rule.Rule)
+		}
+		validatePolicies(t, policies, [][]string{{"alice", "data3", "read"}, {"alice", "data1", "write"}})
+
+		rules, err = a.LoadAuditedRules(context.Background(), []FilterEx{{V0: Equal("alice"), V2: Equal("write")}, {V0: Equal("bob")}})
+		validateNilError(t, err)
+		policies = policies[:0]
+		for _, rule := range rules {
+			policies = append(policies, rule.Rule)
+		}
+		validatePolicies(t, policies, [][]string{{"alice", "data1", "write"}, {"bob", "data2", "write"}})
+	})
+}
+
+func testSoftDelete(t *testing.T, db *sql.DB, driverName, tableName string) {
+	t.Run("SoftDelete", func(t *testing.T) {
+		for _, name := range []string{tableName, tableName + "_migrations"} {
+			if _, err := db.Exec("DROP TABLE IF EXISTS " + name); err != nil {
+				t.Fatal("drop table failed, err: ", err)
+			}
+		}
+
+		// the unique index is recreated by the migration.
+		a, err := NewAdapter(db, driverName, tableName, WithUniqueIndex())
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+		if err = a.AddPolicies("p", "p", testDefaultPolicy); err != nil {
+			t.Fatalf("%s test failed, err: %v", "AddPolicies", err)
+		}
+
+		a, err = NewAdapter(db, driverName, tableName, WithUniqueIndex(), WithSoftDelete(), WithAutoMigrate())
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		e, _ := casbin.NewEnforcer(testRbacModelFile, a)
+		for i := 0; i < 2; i++ {
+			if _, err = e.RemovePolicy("alice", "data1", "read"); err != nil {
+				t.Errorf("%s test failed, err: %v", "RemovePolicy", err)
+			}
+			if _, err = e.AddPolicy("alice", "data1", "read"); err != nil {
+				t.Errorf("%s test failed, err: %v", "AddPolicy", err)
+			}
+		}
+		if _, err = e.RemoveFilteredPolicy(0, "data2_admin"); err != nil {
+			t.Errorf("%s test failed, err: %v", "RemoveFilteredPolicy", err)
+		}
+		if err = e.LoadPolicy(); err != nil {
+			t.Errorf("%s test failed, err: %v", "LoadPolicy", err)
+		}
+		policies, err := e.GetPolicy()
+		validateNilError(t, err)
+		validatePolicies(t, policies, [][]string{{"alice", "data1", "read"}, {"bob", "data2", "write"}})
+
+		var count int
+		if err = db.QueryRow("SELECT COUNT(*) FROM " + tableName).Scan(&count); err != nil || count != 6 {
+			t.Errorf("%s test failed, count: %d, err: %v", "RemovePolicy", count, err)
+		}
+
+		purged, err := a.PurgeDeleted(context.Background(), time.Hour)
+		validateNilError(t, err)
+		if purged != 0 {
+			t.Errorf("%s test failed, purged: %d", "PurgeDeleted", purged)
+		}
+
+		if _, err = db.Exec("UPDATE " + tableName + " SET deleted_at='2000-01-01 00:00:00' WHERE deleted_at IS NOT NULL"); err != nil {
+			t.Fatal("update failed, err: ", err)
+		}
+
+		purged, err = a.PurgeDeleted(context.Background(), time.Hour)
+		validateNilError(t, err)
+		if purged != 4 {
+			t.Errorf("%s test failed, purged: %d", "PurgeDeleted", purged)
+		}
+	})
+}
+
+// wrappedDriver  wraps a driver like sqlhooks.
+type wrappedDriver struct {
+	driver.Driver
+}
+
+// opaqueDriver  wraps a driver which can not be unwrapped, the dialect is detected by the version queries.
+type opaqueDriver struct {
+	driver interface{}
+}
+
+func (d opaqueDriver) Open(name string) (driver.Conn, error) {
+	return d.driver.(driver.Driver).Open(name)
+}
+
+type testConnector struct {
+	driver         driver.Driver
+	dataSourceName string
+}
+
+func (c testConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dataSourceName)
+}
+
+func (c testConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func testDetectDialect(t *testing.T, db *sql.DB, driverName, tableName string) {
+	t.Run("DetectDialect", func(t *testing.T) {
+		want, err := LookupDialect(driverName)
+		if err != nil {
+			t.Fatalf("%s test failed, err: %v", "LookupDialect", err)
+		}
+
+		drivers := map[string]driver.Driver{
+			"driver":  db.Driver(),
+			"wrapped": wrappedDriver{Driver: db.Driver()},
+			"opaque":  opaqueDriver{driver: db.Driver()},
+		}
+
+		for name, drv := range drivers {
+			testDB := sql.OpenDB(testConnector{driver: drv, dataSourceName: testDataSources[driverName]})
+
+			got, err := DetectDialect(context.Background(), testDB)
+			if err != nil {
+				t.Errorf("%s test failed, err: %v", "DetectDialect_"+name, err)
+			} else if got.Name() != want.Name() {
+				t.Errorf("%s test failed, got: %s, want: %s", "DetectDialect_"+name, got.Name(), want.Name())
+			}
+
+			if err = testDB.Close(); err != nil {
+				t.Errorf("%s test failed, err: %v", "Close", err)
+			}
+		}
+
+		a, err := NewAdapterFromDB(db, tableName)
+		if err != nil {
+			t.Fatalf("%s test failed, err: %v", "NewAdapterFromDB", err)
+		}
+
+		if err = a.AddPolicy("p", "p", []string{"alice", "data1", "read"}); err != nil {
+			t.Errorf("%s test failed, err: %v", "AddPolicy", err)
+		}
+	})
+}
+
+func testWithTx(t *testing.T, db *sql.DB, driverName, tableName string) {
+	t.Run("WithTx", func(t *testing.T) {
+		if _, err := db.Exec("DROP TABLE IF EXISTS " + tableName); err != nil {
+			t.Fatal("drop table failed, err: ", err)
+		}
+
+		a, err := NewAdapter(db, driverName, tableName)
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		// the rules of the rolled back transaction and the committed transaction.
+		rules := [][][]string{{{"alice", "admin"}, {"carol", "admin"}}, {{"bob", "admin"}, {"dave", "admin"}}}
+
+		for idx, commit := range []bool{false, true} {
+			tx, err := db.Begin()
+			if err != nil {
+				t.Fatal("begin tx failed, err: ", err)
+			}
+
+			txAdapter := a.WithTx(tx)
+			if err = txAdapter.AddPolicy("g", "g", rules[idx][0]); err != nil {
+				t.Errorf("%s test failed, err: %v", "AddPolicy", err)
+			}
+			if err = txAdapter.AddPolicies("g", "g", rules[idx][1:]); err != nil {
+				t.Errorf("%s test failed, err: %v", "AddPolicies", err)
+			}
+
+			if commit {
+				err = tx.Commit()
+			} else {
+				err = tx.Rollback()
+			}
+			if err != nil {
+				t.Fatal("end tx failed, err: ", err)
+			}
+		}
+
+		e, _ := casbin.NewEnforcer(testRbacModelFile, a)
+		if err = e.LoadPolicy(); err != nil {
+			t.Fatalf("%s test failed, err: %v", "LoadPolicy", err)
+		}
+
+		policy, _ := e.GetGroupingPolicy()
+		validatePolicies(t, policy, rules[1])
+	})
+}
+
+// countingDB  counts the queries, it is used as the read replica.
+type countingDB struct {
+	*sql.DB
+
+	queries int
+}
+
+func (db *countingDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	db.queries++
+
+	return db.DB.QueryContext(ctx, query, args...)
+}
+
+func testReadDB(t *testing.T, db *sql.DB, driverName, tableName string) {
+	t.Run("ReadDB", func(t *testing.T) {
+		if _, err := db.Exec("DROP TABLE IF EXISTS " + tableName); err != nil {
+			t.Fatal("drop table failed, err: ", err)
+		}
+
+		readDB := &countingDB{DB: db}
+
+		a, err := NewAdapter(db, driverName, tableName, WithReadDB(readDB))
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		if err = a.AddPolicies("p", "p", testDefaultPolicy); err != nil {
+			t.Errorf("%s test failed, err: %v", "AddPolicies", err)
+		}
+		if _, err = a.UpdateFilteredPolicies("p", "p", [][]string{{"alice", "data1", "write"}}, 0, "alice"); err != nil {
+			t.Errorf("%s test failed, err: %v", "UpdateFilteredPolicies", err)
+		}
+		if readDB.queries != 0 {
+			t.Errorf("%s test failed, the writes read %d times from the read db", "ReadDB", readDB.queries)
+		}
+
+		e, _ := casbin.NewEnforcer(testRbacModelFile, a)
+		if err = e.LoadPolicy(); err != nil {
+			t.Fatalf("%s test failed, err: %v", "LoadPolicy", err)
+		}
+		// NewEnforcer loads the policy too.
+		loads := readDB.queries
+		if loads != 2 {
+			t.Errorf("%s test failed, got %d queries from the read db, want: 2", "LoadPolicy", loads)
+		}
+
+		m := e.GetModel()
+		m.ClearPolicy()
+		if err = a.LoadPolicyCtx(ReadFromPrimary(context.Background()), m); err != nil {
+			t.Fatalf("%s test failed, err: %v", "LoadPolicyCtx", err)
+		}
+		if readDB.queries != loads {
+			t.Errorf("%s test failed, got %d queries from the read db, want: %d", "ReadFromPrimary", readDB.queries, loads)
+		}
+
+		policy, _ := e.GetPolicy()
+		validatePolicies(t, policy, [][]string{{"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}, {"alice", "data1", "write"}})
+	})
+}
+
+func testBatchInsert(t *testing.T, db *sql.DB, driverName, tableName string) {
+	t.Run("BatchInsert", func(t *testing.T) {
+		if _, err := db.Exec("DROP TABLE IF EXISTS " + tableName); err != nil {
+			t.Fatal("drop table failed, err: ", err)
+		}
+
+		a, err := NewAdapter(db, driverName, tableName, WithBatchSize(100))
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		// the rules are more than the bind parameter limits of the databases.
+		rules := make([][]string, 0, 3000)
+		for i := 0; i < cap(rules); i++ {
+			rules = append(rules, []string{fmt.Sprintf("user%d", i), "data1", "read"})
+		}
+
+		if err = a.AddPolicies("p", "p", rules[:250]); err != nil {
+			t.Errorf("%s test failed, err: %v", "AddPolicies", err)
+		}
+
+		e, _ := casbin.NewEnforcer(testRbacModelFile, a)
+		if policy, _ := e.GetPolicy(); len(policy) != 250 {
+			t.Errorf("%s test failed, got %d rules, want: %d", "AddPolicies", len(policy), 250)
+		}
+
+		// PostgreSQL saves the rules by COPY FROM STDIN, the other databases fall back to the inserts.
+		a, err = NewAdapter(db, driverName, tableName, WithCopyFrom())
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		e, _ = casbin.NewEnforcer(testRbacModelFile, a)
+		if _, err = e.AddPoliciesEx(rules); err != nil {
+			t.Errorf("%s test failed, err: %v", "AddPoliciesEx", err)
+		}
+		if err = e.SavePolicy(); err != nil {
+			t.Errorf("%s test failed, err: %v", "SavePolicy", err)
+		}
+		if err = e.LoadPolicy(); err != nil {
+			t.Errorf("%s test failed, err: %v", "LoadPolicy", err)
+		}
+
+		policy, _ := e.GetPolicy()
+		validatePolicies(t, policy, rules)
+
+		// the Adapter works without the cached statements after Close.
+		if err = a.Close(); err != nil {
+			t.Errorf("%s test failed, err: %v", "Close", err)
+		}
+		if _, err = e.RemovePolicy(rules[0]); err != nil {
+			t.Errorf("%s test failed, err: %v", "RemovePolicy", err)
+		}
+	})
+}
+
+func testDiffSave(t *testing.T, db *sql.DB, driverName, tableName string) {
+	t.Run("DiffSave", func(t *testing.T) {
+		initPolicy(t, db, driverName, tableName)
+
+		a, err := NewAdapter(db, driverName, tableName, WithDiffSave())
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		e, _ := casbin.NewEnforcer(testRbacModelFile, a)
+		e.EnableAutoSave(false)
+
+		if _, err = e.RemovePolicy("alice", "data1", "read"); err != nil {
+			t.Errorf("%s test failed, err: %v", "RemovePolicy", err)
+		}
+		if _, err = e.AddPolicies([][]string{{"alice", "data1", "write"}, {"carol", "data3", "read"}}); err != nil {
+			t.Errorf("%s test failed, err: %v", "AddPolicies", err)
+		}
+
+		result, err := a.SavePolicyDiff(context.Background(), e.GetModel())
+		if err != nil {
+			t.Fatalf("%s test failed, err: %v", "SavePolicyDiff", err)
+		}
+		if want := (SaveResult{Added: 2, Removed: 1}); result != want {
+			t.Errorf("%s test failed, got: %+v, want: %+v", "SavePolicyDiff", result, want)
+		}
+
+		result, err = a.SavePolicyDiff(context.Background(), e.GetModel())
+		if err != nil {
+			t.Fatalf("%s test failed, err: %v", "SavePolicyDiff", err)
+		}
+		if result != (SaveResult{}) {
+			t.Errorf("%s test failed, got: %+v, want no changes", "SavePolicyDiff", result)
+		}
+
+		if _, err = e.RemovePolicy("carol", "data3", "read"); err != nil {
+			t.Errorf("%s test failed, err: %v", "RemovePolicy", err)
+		}
+		if err = e.SavePolicy(); err != nil {
+			t.Errorf("%s test failed, err: %v", "SavePolicy", err)
+		}
+		if err = e.LoadPolicy(); err != nil {
+			t.Errorf("%s test failed, err: %v", "LoadPolicy", err)
+		}
+
+		policy, _ := e.GetPolicy()
+		validatePolicies(t, policy, [][]string{{"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}, {"alice", "data1", "write"}})
+	})
+}
+
+func testForEachRule(t *testing.T, db *sql.DB, driverName, tableName string) {
+	t.Run("ForEachRule", func(t *testing.T) {
+		initPolicy(t, db, driverName, tableName)
+
+		a, err := NewAdapter(db, driverName, tableName)
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		var policy [][]string
+
+		err = a.ForEachRule(context.Background(), &Filter{PType: []string{"p"}, V1: []string{"data2"}}, func(ptype string, rule []string) error {
+			policy = append(policy, rule)
+
+			return nil
+		})
+		if err != nil {
+			t.Errorf("%s test failed, err: %v", "ForEachRule", err)
+		}
+
+		validatePolicies(t, policy, [][]string{{"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"}})
+
+		// the filters are the same as LoadFilteredPolicy.
+		for _, filter := range []interface{}{
+			FilterEx{PType: Equal("p"), V0: Prefix("data2"), V2: NotEqual("write")},
+			[]Filter{{V0: []string{"data2_admin"}, V2: []string{"read"}}},
+			"p, data2_admin, , read",
+		} {
+			policy = nil
+
+			err = a.ForEachRule(context.Background(), filter, func(ptype string, rule []string) error {
+				policy = append(policy, rule)
+
+				return nil
+			})
+			if err != nil {
+				t.Errorf("%s test failed, filter: %v, err: %v", "ForEachRule", filter, err)
+			}
+
+			validatePolicies(t, policy, [][]string{{"data2_admin", "data2", "read"}})
+		}
+
+		var filterErr *InvalidFilterError
+		if err = a.ForEachRule(context.Background(), 1, func(string, []string) error { return nil }); !errors.As(err, &filterErr) {
+			t.Errorf("%s test failed, want *InvalidFilterError, got: %v", "ForEachRule", err)
+		}
+
+		// the error of fn stops the iteration.
+		errStop := errors.New("stop")
+		count := 0
+
+		err = a.ForEachRule(context.Background(), nil, func(string, []string) error {
+			count++
+
+			return errStop
+		})
+		if !errors.Is(err, errStop) || count != 1 {
+			t.Errorf("%s test failed, err: %v, count: %d", "ForEachRule", err, count)
+		}
+	})
+}
+
+func testPageSize(t *testing.T, db *sql.DB, driverName, tableName string) {
+	t.Run("PageSize", func(t *testing.T) {
+		for _, name := range []string{tableName, tableName + "_migrations"} {
+			if _, err := db.Exec("DROP TABLE IF EXISTS " + name); err != nil {
+				t.Fatal("drop table failed, err: ", err)
+			}
+		}
+
+		// the table is created without the id column.
+		a, err := NewAdapter(db, driverName, tableName)
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		rules := make([][]string, 0, 25)
+		for i := 0; i < cap(rules); i++ {
+			rules = append(rules, []string{fmt.Sprintf("user%d", i), fmt.Sprintf("data%d", i%2), "read"})
+		}
+
+		if err = a.AddPolicies("p", "p", rules); err != nil {
+			t.Fatalf("%s test failed, err: %v", "AddPolicies", err)
+		}
+
+		a, err = NewAdapter(db, driverName, tableName, WithPageSize(10), WithSnapshot(), WithAutoMigrate())
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		e, _ := casbin.NewEnforcer(testRbacModelFile, a)
+		if err = e.LoadPolicy(); err != nil {
+			t.Fatalf("%s test failed, err: %v", "LoadPolicy", err)
+		}
+
+		policy, _ := e.GetPolicy()
+		validatePolicies(t, policy, rules)
+
+		if err = e.LoadFilteredPolicy(&Filter{V1: []string{"data1"}}); err != nil {
+			t.Fatalf("%s test failed, err: %v", "LoadFilteredPolicy", err)
+		}
+
+		want := make([][]string, 0, len(rules)/2)
+		for _, rule := range rules {
+			if rule[1] == "data1" {
+				want = append(want, rule)
+			}
+		}
+
+		policy, _ = e.GetPolicy()
+		validatePolicies(t, policy, want)
+
+		// the unique index migration keeps the id column.
+		a, err = NewAdapter(db, driverName, tableName, WithPageSize(10), WithUniqueIndex(), WithAutoMigrate())
+		if err != nil {
+			t.Fatal("sqladapter NewAdapter failed, err: ", err)
+		}
+
+		e, _ = casbin.NewEnforcer(testRbacModelFile, a)
+		policy, _ = e.GetPolicy()
+		validatePolicies(t, policy, rules)
+	})
+}
+
+func testFilterEx(t *testing.T, db *sql.DB, driverName, tableName string) {
+	initPolicy(t, db, driverName, tableName)
+
+	a, err := NewAdapter(db, driverName, tableName)
+	if err != nil {
+		t.Fatal("sqladapter NewAdapter failed, err: ", err)
+	}
+
+	rules := [][]string{
+		{"alice", "/orgs/42/a", "read"},
+		{"alice", "/orgs/42/b/c", "read"},
+		{"bob", "/orgs/420/x", "read"},
+		{"bob", "/orgs/4_/y", "read"},
+		{"bob", "/orgs/4%/z", "write"},
+	}
+	if err = a.AddPolicies("p", "p", rules); err != nil {
+		t.Fatalf("%s test failed, err: %v", "AddPolicies", err)
+	}
+
+	e, _ := casbin.NewEnforcer(testRbacModelFile, a)
+
+	tests := []struct {
+		name         string
+		filterPolicy *FilterEx
+		expectPolicy [][]string
+	}{
+		{
+			name:         "01_prefix",
+			filterPolicy: &FilterEx{V1: Prefix("/orgs/42/")},
+			expectPolicy: rules[:2],
+		},
+		{
+			name:         "02_prefix_wildcards",
+			filterPolicy: &FilterEx{V1: Prefix("/orgs/4_/", "/orgs/4%/")},
+			expectPolicy: rules[3:],
+		},
+		{
+			name:         "03_glob",
+			filterPolicy: &FilterEx{PType: Equal("p"), V1: Glob("/orgs/*/b/*", "/orgs/42?/*")},
+			expectPolicy: rules[1:3],
+		},
+		{
+			name:         "04_like",
+			filterPolicy: &FilterEx{V0: Equal("bob"), V1: Like("/orgs/4_/%"), V2: Equal("read")},
+			expectPolicy: rules[3:4],
+		},
+		{
+			name:         "05_not_equal",
+			filterPolicy: &FilterEx{PType: NotEqual("g"), V0: NotEqual("alice", "data2_admin"), V1: Prefix("/")},
+			expectPolicy: rules[2:],
+		},
+	}
+	for _, tt := range tests {
+		t.Run("FilterEx_"+tt.name, func(t *testing.T) {
+			if err = e.LoadFilteredPolicy(tt.filterPolicy); err != nil {
+				t.Errorf("%s LoadFilteredPolicy test failed, err: %v", tt.name, err)
+			}
+
+			policies, err := e.GetPolicy()
+			validateNilError(t, err)
+			validatePolicies(t, policies, tt.expectPolicy)
+		})
+	}
+
+	t.Run("FilterEx_06_not_equal_ptype", func(t *testing.T) {
+		if err = e.LoadFilteredPolicy(&FilterEx{PType: NotEqual("p")}); err != nil {
+			t.Errorf("%s LoadFilteredPolicy test failed, err: %v", "06_not_equal_ptype", err)
+		}
+
+		policies, err := e.GetPolicy()
+		validateNilError(t, err)
+		validatePolicies(t, policies, [][]string{})
+
+		policies, err = e.GetGroupingPolicy()
+		validateNilError(t, err)
+		validatePolicies(t, policies, [][]string{{"alice", "data2_admin"}})
+	})
+
+	t.Run("FilterEx_07_or", func(t *testing.T) {
+		filters := []*Filter{
+			{PType: []string{"p"}, V0: []string{"bob"}, V2: []string{"write"}},
+			{PType: []string{"g"}, V0: []string{"alice"}},
+			{PType: []string{"p"}, V0: []string{"bob"}, V2: []string{"write"}},
+		}
+		if err = e.LoadFilteredPolicy(filters); err != nil {
+			t.Errorf("%s LoadFilteredPolicy test failed, err: %v", "07_or", err)
+		}
+
+		if !e.IsFiltered() {
+			t.Errorf("%s test failed, the policy is not filtered", "07_or")
+		}
+
+		policies, err := e.GetPolicy()
+		validateNilError(t, err)
+		validatePolicies(t, policies, [][]string{{"bob", "data2", "write"}, {"bob", "/orgs/4%/z", "write"}})
+
+		policies, err = e.GetGroupingPolicy()
+		validateNilError(t, err)
+		validatePolicies(t, policies, [][]string{{"alice", "data2_admin"}})
+
+		if err = e.LoadFilteredPolicy([]*FilterEx{{V1: Prefix("/orgs/42/")}, {V1: Glob("*/y")}}); err != nil {
+			t.Errorf("%s LoadFilteredPolicy test failed, err: %v", "07_or", err)
+		}
+
+		policies, err = e.GetPolicy()
+		validateNilError(t, err)
+		validatePolicies(t, policies, [][]string{rules[0], rules[1], rules[3]})
+
+		if err = e.LoadFilteredPolicy([]*Filter{}); err == nil {
+			t.Errorf("%s test failed, the empty filter list is loaded", "07_or")
+		}
+	})
+}
+
+func testFilteredSave(t *testing.T, db *sql.DB, driverName, tableName string) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "01_replace"},
+		{name: "02_diff", opts: []Option{WithDiffSave()}},
+	}
+	for _, tt := range tests {
+		t.Run("FilteredSave_"+tt.name, func(t *testing.T) {
+			initPolicy(t, db, driverName, tableName)
+
+			a, err := NewAdapter(db, driverName, tableName, tt.opts...)
+			if err != nil {
+				t.Fatal("sqladapter NewAdapter failed, err: ", err)
+			}
+
+			tenants := [][]string{{"alice", "/t1/a", "read"}, {"bob", "/t1/b", "read"}, {"carol", "/t2/a", "read"}}
+			if err = a.AddPolicies("p", "p", tenants); err != nil {
+				t.Fatalf("%s test failed, err: %v", "AddPolicies", err)
+			}
+
+			e, _ := casbin.NewEnforcer(testRbacModelFile, a)
+			e.EnableAutoSave(false)
+
+			if err = e.LoadFilteredPolicy(&FilterEx{PType: Equal("p"), V1: Prefix("/t1/")}); err != nil {
+				t.Fatalf("%s test failed, err: %v", "LoadFilteredPolicy", err)
+			}
+			if _, err = e.RemovePolicy("bob", "/t1/b", "read"); err != nil {
+				t.Errorf("%s test failed, err: %v", "RemovePolicy", err)
+			}
+			if _, err = e.AddPolicy("dave", "/t1/c", "write"); err != nil {
+				t.Errorf("%s test failed, err: %v", "AddPolicy", err)
+			}
+
+			// the enforcer refuses to save the filtered policy, so it is saved by the adapter.
+			if err = a.SavePolicy(e.GetModel()); err != nil {
+				t.Fatalf("%s test failed, err: %v", "SavePolicy", err)
+			}
+
+			// the rule out of the filter is rejected by every save, it is not inserted again and again.
+			if _, err = e.AddPolicy("eve", "/t2/x", "read"); err != nil {
+				t.Errorf("%s test failed, err: %v", "AddPolicy", err)
+			}
+			for i := 0; i < 2; i++ {
+				if err = a.SavePolicy(e.GetModel()); !errors.Is(err, ErrRuleOutOfFilter) {
+					t.Errorf("%s test failed, want ErrRuleOutOfFilter, got: %v", "SavePolicy", err)
+				}
+			}
+			if _, err = e.RemovePolicy("eve", "/t2/x", "read"); err != nil {
+				t.Errorf("%s test failed, err: %v", "RemovePolicy", err)
+			}
+			for i := 0; i < 2; i++ {
+				if err = a.SavePolicy(e.GetModel()); err != nil {
+					t.Fatalf("%s test failed, err: %v", "SavePolicy", err)
+				}
+			}
+
+			// the loaded rules are matched by the database as the load, e.g. the case-insensitive LIKE of SQLite.
+			if err = e.LoadFilteredPolicy(&FilterEx{V0: Prefix("ALI")}); err != nil {
+				t.Fatalf("%s test failed, err: %v", "LoadFilteredPolicy", err)
+			}
+			if err = a.SavePolicy(e.GetModel()); err != nil {
+				t.Errorf("%s test failed, err: %v", "SavePolicy", err)
+			}
+
+			if err = e.LoadPolicy(); err != nil {
+				t.Fatalf("%s test failed, err: %v", "LoadPolicy", err)
+			}
+
+			policy, _ := e.GetPolicy()
+			validatePolicies(t, policy, [][]string{
+				{"alice", "data1", "read"}, {"bob", "data2", "write"}, {"data2_admin", "data2", "read"}, {"data2_admin", "data2", "write"},
+				{"alice", "/t1/a", "read"}, {"dave", "/t1/c", "write"}, {"carol", "/t2/a", "read"},
+			})
+
+			policy, _ = e.GetGroupingPolicy()
+			validatePolicies(t, policy, [][]string{{"alice", "data2_admin"}})
+		})
+	}
+}
+
 func validatePolicies(t *testing.T, getPolicy, wantPolicy [][]string) {
 	t.Helper()
 
@@ -34,7 +34,8 @@ const (
 )
 
 var (
-	testDBs = map[string]*sql.DB{}
+	testDBs         = map[string]*sql.DB{}
+	testDataSources = map[string]string{}
 )
 
 func TestMain(m *testing.M) {
@@ -158,4 +159,5 @@ func loadDB(dataSources map[string]string, driverName string) {
 	}
 
 	testDBs[driverName] = db
+	testDataSources[driverName] = dataSourceName
 }